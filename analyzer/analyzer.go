@@ -0,0 +1,318 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/mochatek/frolang/ast"
+	"github.com/mochatek/frolang/evaluator"
+	"github.com/mochatek/frolang/object"
+)
+
+// Name which is never reported, as it is meant to be ignored. ie, match wildcard
+const ignored = "_"
+
+// Warning for an identifier which is not declared
+const undefinedWarning = "Undefined variable: %s"
+
+// Variable declared in a scope
+// Only the variables declared by let statement are reported when unused
+// Global variables are declared in the top level scope of the program
+type binding struct {
+	location string
+	name     string
+	used     bool
+	global   bool
+}
+
+// Variables declared in a block, with link to the enclosing block
+// Scope is open when a file was imported into it without alias, as the imported names are only known at runtime
+type scope struct {
+	outer    *scope
+	bindings map[string]*binding
+	open     bool
+}
+
+// Function whose body is analyzed after the enclosing code,
+// as the body can refer variables declared after the function (ie, recursion)
+type pendingFunction struct {
+	function *ast.FunctionLiteral
+	scope    *scope
+}
+
+type analyzer struct {
+	defined  func(name string) bool
+	pending  []pendingFunction
+	bindings []*binding
+	warnings []string
+}
+
+// Analyzes the program without evaluating it, and returns the warnings
+// Reports the identifiers which are not declared in any reachable scope and are not builtins
+// Reports the variables declared by let statement, which are never used
+// Names starting with _ are not reported as unused
+func Analyze(program *ast.Program) []string {
+	analyzer := &analyzer{defined: evaluator.IsBuiltin}
+	analyzer.analyze(program)
+	analyzer.reportUnused(true)
+	return analyzer.warnings
+}
+
+// Analyzes the program considering the variables of the environment as declared
+// Unused global variables are not reported, as they can be used by later code (REPL)
+func AnalyzeIn(program *ast.Program, env *object.Environment) []string {
+	analyzer := &analyzer{defined: func(name string) bool {
+		if _, ok := env.Get(name); ok {
+			return true
+		}
+		return evaluator.IsBuiltin(name)
+	}}
+	analyzer.analyze(program)
+	analyzer.reportUnused(false)
+	return analyzer.warnings
+}
+
+// Returns the warnings which are not already reported by the result of evaluation
+// If evaluation stopped at an undefined variable, then the error reports it and its warning is left out
+func Unreported(warnings []string, result object.Object) []string {
+	err, ok := result.(*object.Error)
+	if !ok {
+		return warnings
+	}
+	unreported := []string{}
+	for _, warning := range warnings {
+		var name, location string
+		if _, scanErr := fmt.Sscanf(warning, undefinedWarning+" at %s", &name, &location); scanErr == nil &&
+			err.Message == fmt.Sprintf(evaluator.UndefinedIdentifierError, name, location) {
+			continue
+		}
+		unreported = append(unreported, warning)
+	}
+	return unreported
+}
+
+// Report the let variables which are never used. Global variables are reported only if asked
+func (analyzer *analyzer) reportUnused(globals bool) {
+	for _, binding := range analyzer.bindings {
+		if !binding.used && binding.name[0] != '_' && (globals || !binding.global) {
+			analyzer.warn(binding.location, "Unused variable: %s", binding.name)
+		}
+	}
+}
+
+// Analyze the top level statements in the global scope, followed by the function bodies
+func (analyzer *analyzer) analyze(program *ast.Program) {
+	global := newScope(nil)
+	for _, statement := range program.Statements {
+		analyzer.statement(statement, global)
+	}
+	for len(analyzer.pending) > 0 {
+		pending := analyzer.pending[0]
+		analyzer.pending = analyzer.pending[1:]
+		functionScope := newScope(pending.scope)
+		for _, parameter := range pending.function.Parameters {
+			functionScope.declare(parameter)
+		}
+		analyzer.block(pending.function.Body, functionScope)
+	}
+}
+
+func newScope(outer *scope) *scope {
+	return &scope{outer: outer, bindings: make(map[string]*binding)}
+}
+
+// Declare the variable in the scope, shadowing any variable with same name from enclosing scopes
+func (scope *scope) declare(identifier *ast.Identifier) *binding {
+	binding := &binding{location: identifier.Token.Location, name: identifier.Value}
+	scope.bindings[identifier.Value] = binding
+	return binding
+}
+
+// Declare a let variable, which is reported if it is never used
+func (analyzer *analyzer) declareVariable(identifier *ast.Identifier, scope *scope) *binding {
+	binding := scope.declare(identifier)
+	binding.global = scope.outer == nil
+	analyzer.bindings = append(analyzer.bindings, binding)
+	return binding
+}
+
+// Mark the variable as used in the innermost scope declaring it
+// Report it if no scope declares it and it is not defined outside the program
+func (analyzer *analyzer) use(identifier *ast.Identifier, scope *scope) {
+	for current := scope; current != nil; current = current.outer {
+		if binding, ok := current.bindings[identifier.Value]; ok {
+			binding.used = true
+			return
+		}
+		if current.open {
+			return
+		}
+	}
+	if !analyzer.defined(identifier.Value) {
+		analyzer.warn(identifier.Token.Location, undefinedWarning, identifier.Value)
+	}
+}
+
+func (analyzer *analyzer) warn(location string, format string, arguments ...interface{}) {
+	analyzer.warnings = append(analyzer.warnings, fmt.Sprintf(format, arguments...)+" at "+location)
+}
+
+// Statements declare variables in the scope, following the scoping rules of the evaluator
+func (analyzer *analyzer) statement(statement ast.Statement, scope *scope) {
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		analyzer.expression(statement.Value, scope)
+		analyzer.declareVariable(statement.Name, scope)
+	case *ast.ExportStatement:
+		analyzer.expression(statement.Statement.Value, scope)
+		analyzer.declareVariable(statement.Statement.Name, scope).used = true
+	case *ast.ReturnStatement:
+		analyzer.expression(statement.ReturnValue, scope)
+	case *ast.YieldStatement:
+		analyzer.expression(statement.Value, scope)
+	case *ast.ExpressionStatement:
+		analyzer.expression(statement.Expression, scope)
+	case *ast.DeferStatement:
+		analyzer.expression(statement.Expression, scope)
+	case *ast.BlockStatement:
+		analyzer.block(statement, scope)
+	case *ast.ForStatement:
+		analyzer.expression(statement.Iterator, scope)
+		loopScope := newScope(scope)
+		loopScope.declare(statement.Element)
+		analyzer.expression(statement.Guard, loopScope)
+		analyzer.block(statement.Body, loopScope)
+	case *ast.WhileStatement:
+		loopScope := newScope(scope)
+		analyzer.expression(statement.Condition, loopScope)
+		analyzer.block(statement.Body, loopScope)
+	case *ast.TryStatement:
+		tryScope := newScope(scope)
+		analyzer.block(statement.Try, tryScope)
+		tryScope.declare(statement.Error)
+		analyzer.block(statement.Catch, tryScope)
+		analyzer.block(statement.Finally, tryScope)
+	case *ast.WithStatement:
+		analyzer.expression(statement.Resource, scope)
+		withScope := newScope(scope)
+		withScope.declare(statement.Name)
+		analyzer.block(statement.Body, withScope)
+	case *ast.ImportStatement:
+		if statement.Alias != nil {
+			scope.declare(statement.Alias)
+		} else if name, ok := evaluator.BuiltinModuleName(statement.Path.Value); ok {
+			scope.declare(&ast.Identifier{Token: statement.Token, Value: name})
+		} else {
+			scope.open = true
+		}
+	}
+}
+
+// Block statements are evaluated in their own scope
+func (analyzer *analyzer) block(block *ast.BlockStatement, scope *scope) {
+	if block == nil {
+		return
+	}
+	blockScope := newScope(scope)
+	for _, statement := range block.Statements {
+		analyzer.statement(statement, blockScope)
+	}
+}
+
+func (analyzer *analyzer) expressions(expressions []ast.Expression, scope *scope) {
+	for _, expression := range expressions {
+		analyzer.expression(expression, scope)
+	}
+}
+
+// Expressions use the variables. Function bodies are analyzed later
+func (analyzer *analyzer) expression(expression ast.Expression, scope *scope) {
+	switch expression := expression.(type) {
+	case *ast.Identifier:
+		analyzer.use(expression, scope)
+	case *ast.PrefixExpression:
+		analyzer.expression(expression.Right, scope)
+	case *ast.PostfixExpression:
+		analyzer.use(expression.Variable, scope)
+	case *ast.InfixExpression:
+		analyzer.expression(expression.Left, scope)
+		analyzer.expression(expression.Right, scope)
+	case *ast.ComparisonExpression:
+		analyzer.expressions(expression.Operands, scope)
+	case *ast.AssignExpression:
+		analyzer.use(expression.Variable, scope)
+		if expression.Slice != nil {
+			analyzer.expression(expression.Slice, scope)
+		}
+		analyzer.expression(expression.Value, scope)
+	case *ast.IndexExpression:
+		analyzer.expression(expression.Array, scope)
+		analyzer.expression(expression.Index, scope)
+	case *ast.SliceExpression:
+		analyzer.expression(expression.Left, scope)
+		analyzer.expression(expression.Start, scope)
+		analyzer.expression(expression.End, scope)
+		analyzer.expression(expression.Step, scope)
+	case *ast.SpreadExpression:
+		analyzer.expression(expression.Value, scope)
+	case *ast.MemberExpression:
+		analyzer.expression(expression.Object, scope)
+	case *ast.IfExpression:
+		analyzer.expression(expression.Condition, scope)
+		analyzer.block(expression.Consequence, scope)
+		analyzer.block(expression.Alternate, scope)
+	case *ast.CallExpression:
+		analyzer.expression(expression.Function, scope)
+		analyzer.expressions(expression.Arguments, scope)
+	case *ast.ArrayLiteral:
+		analyzer.expressions(expression.Elements, scope)
+	case *ast.TupleLiteral:
+		analyzer.expressions(expression.Elements, scope)
+	case *ast.HashLiteral:
+		for key, value := range expression.Pairs {
+			analyzer.expression(key, scope)
+			analyzer.expression(value, scope)
+		}
+	case *ast.ComprehensionExpression:
+		analyzer.expression(expression.Iterator, scope)
+		elementScope := newScope(scope)
+		elementScope.declare(expression.Element)
+		analyzer.expression(expression.Guard, elementScope)
+		analyzer.expression(expression.Key, elementScope)
+		analyzer.expression(expression.Value, elementScope)
+	case *ast.MatchExpression:
+		analyzer.expression(expression.Subject, scope)
+		for _, arm := range expression.Arms {
+			armScope := newScope(scope)
+			analyzer.pattern(arm.Pattern, scope, armScope)
+			analyzer.expression(arm.Guard, armScope)
+			analyzer.block(arm.Body, armScope)
+		}
+	case *ast.FunctionLiteral:
+		analyzer.pending = append(analyzer.pending, pendingFunction{function: expression, scope: scope})
+	}
+}
+
+// Identifiers in a match pattern declare variables in the arm scope, except the wildcard
+// Array and hash patterns are matched element-wise. Keys of hash patterns and other expressions use variables
+func (analyzer *analyzer) pattern(pattern ast.Expression, scope *scope, armScope *scope) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value != ignored {
+			armScope.declare(pattern)
+		}
+	case *ast.ArrayLiteral:
+		for _, element := range pattern.Elements {
+			if spread, ok := element.(*ast.SpreadExpression); ok {
+				element = spread.Value
+			}
+			analyzer.pattern(element, scope, armScope)
+		}
+	case *ast.HashLiteral:
+		for key, value := range pattern.Pairs {
+			analyzer.expression(key, scope)
+			analyzer.pattern(value, scope, armScope)
+		}
+	default:
+		analyzer.expression(pattern, scope)
+	}
+}
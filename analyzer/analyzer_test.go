@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mochatek/frolang/ast"
+	"github.com/mochatek/frolang/lexer"
+	"github.com/mochatek/frolang/object"
+	"github.com/mochatek/frolang/parser"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+	par := parser.New(lexer.New(input))
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		t.Fatalf("Parse errors for %q: %v", input, par.Errors())
+	}
+	return program
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`let x = 1; print(x)`, nil},
+		{`print(y)`, []string{"Undefined variable: y at 1:7"}},
+		{`let x = 1`, []string{"Unused variable: x at 1:5"}},
+		{`let _x = 1`, nil},
+		{`let f = fn() { g() }; let g = fn() { 1 }; f()`, nil},
+		{`let f = fn(a) { let b = 2; a }; f(1)`, []string{"Unused variable: b at 1:21"}},
+		{"for i in [1] { print(i) }\nprint(i)", []string{"Undefined variable: i at 2:7"}},
+		{`import "math"; math.sqrt(4)`, nil},
+		{`export let x = 1`, nil},
+	}
+	for _, test := range tests {
+		warnings := Analyze(parse(t, test.input))
+		if len(warnings) == 0 && len(test.expected) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(warnings, test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.input, test.expected, warnings)
+		}
+	}
+}
+
+func TestAnalyzeIn(t *testing.T) {
+	env := object.NewEnvironment()
+	env.Set("x", &object.Integer{Value: 1})
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`x + 1`, nil},
+		{`let y = x`, nil},
+		{`fn() { let z = 1; 2 }`, []string{"Unused variable: z at 1:12"}},
+		{`z`, []string{"Undefined variable: z at 1:1"}},
+	}
+	for _, test := range tests {
+		warnings := AnalyzeIn(parse(t, test.input), env)
+		if len(warnings) == 0 && len(test.expected) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(warnings, test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.input, test.expected, warnings)
+		}
+	}
+}
+
+func TestUnreported(t *testing.T) {
+	warnings := []string{"Undefined variable: a at 1:1", "Undefined variable: b at 2:1", "Unused variable: c at 3:5"}
+	tests := []struct {
+		result   object.Object
+		expected []string
+	}{
+		{&object.Integer{Value: 1}, warnings},
+		{&object.Error{Message: "Identifier: a not found at 1:1"}, warnings[1:]},
+		{&object.Error{Message: "Identifier: a not found at 5:1"}, warnings},
+		{&object.Error{Message: "Division by zero"}, warnings},
+	}
+	for _, test := range tests {
+		if unreported := Unreported(warnings, test.result); !reflect.DeepEqual(unreported, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.result.Inspect(), test.expected, unreported)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/mochatek/frolang/token"
@@ -9,8 +10,45 @@ import (
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Span() Span
 }
 
+// Position of a character in the source code
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Span of the source code from which a node was parsed, from its first character to the last
+type Span struct {
+	Start Position
+	End   Position
+}
+
+func (span Span) String() string {
+	return fmt.Sprintf("%d:%d-%d:%d", span.Start.Line, span.Start.Column, span.End.Line, span.End.Column)
+}
+
+// Creates the span from the first token of a node to its last token
+func NewSpan(first token.Token, last token.Token) Span {
+	return Span{Start: parsePosition(first.Location), End: parsePosition(last.End)}
+}
+
+// Helper function to convert location of the form line:col into position
+func parsePosition(location string) Position {
+	var position Position
+	fmt.Sscanf(location, "%d:%d", &position.Line, &position.Column)
+	return position
+}
+
+// Embedded in the nodes to hold their span, which is set by the parser
+type Spanned struct {
+	NodeSpan Span
+}
+
+func (spanned *Spanned) Span() Span        { return spanned.NodeSpan }
+func (spanned *Spanned) SetSpan(span Span) { spanned.NodeSpan = span }
+
 type Statement interface {
 	Node
 	statementNode()
@@ -33,6 +71,16 @@ func (program *Program) TokenLiteral() string {
 		return ""
 	}
 }
+
+// Span of the program is from its first statement to the last
+func (program *Program) Span() Span {
+	if len(program.Statements) == 0 {
+		return Span{}
+	}
+	first := program.Statements[0].Span()
+	last := program.Statements[len(program.Statements)-1].Span()
+	return Span{Start: first.Start, End: last.End}
+}
 func (program *Program) String() string {
 	var str strings.Builder
 	for _, statement := range program.Statements {
@@ -42,6 +90,7 @@ func (program *Program) String() string {
 }
 
 type LetStatement struct {
+	Spanned
 	Token token.Token
 	Name  *Identifier
 	Value Expression
@@ -62,6 +111,7 @@ func (letStatement *LetStatement) String() string {
 }
 
 type ReturnStatement struct {
+	Spanned
 	Token       token.Token
 	ReturnValue Expression
 }
@@ -71,14 +121,15 @@ func (returnStatement *ReturnStatement) TokenLiteral() string { return returnSta
 func (returnStatement *ReturnStatement) String() string {
 	var str strings.Builder
 	str.WriteString(returnStatement.TokenLiteral())
-	str.WriteString(" ")
 	if returnStatement.ReturnValue != nil {
+		str.WriteString(" ")
 		str.WriteString(returnStatement.ReturnValue.String())
 	}
 	return str.String()
 }
 
 type ExpressionStatement struct {
+	Spanned
 	Token      token.Token
 	Expression Expression
 }
@@ -96,6 +147,7 @@ func (expressionStatement *ExpressionStatement) String() string {
 }
 
 type BlockStatement struct {
+	Spanned
 	Token      token.Token
 	Statements []Statement
 }
@@ -114,9 +166,11 @@ func (blockStatement *BlockStatement) String() string {
 }
 
 type ForStatement struct {
+	Spanned
 	Token    token.Token
 	Element  *Identifier
 	Iterator Expression
+	Guard    Expression
 	Body     *BlockStatement
 }
 
@@ -129,12 +183,17 @@ func (forStatement *ForStatement) String() string {
 	str.WriteString(forStatement.Element.String())
 	str.WriteString(" in ")
 	str.WriteString(forStatement.Iterator.String())
+	if forStatement.Guard != nil {
+		str.WriteString(" if ")
+		str.WriteString(forStatement.Guard.String())
+	}
 	str.WriteString(") ")
 	str.WriteString(forStatement.Body.String())
 	return str.String()
 }
 
 type WhileStatement struct {
+	Spanned
 	Token     token.Token
 	Condition Expression
 	Body      *BlockStatement
@@ -153,6 +212,7 @@ func (whileStatement *WhileStatement) String() string {
 }
 
 type BreakStatement struct {
+	Spanned
 	Token token.Token
 }
 
@@ -161,6 +221,7 @@ func (breakStatement *BreakStatement) TokenLiteral() string { return breakStatem
 func (breakStatement *BreakStatement) String() string       { return "break" }
 
 type ContinueStatement struct {
+	Spanned
 	Token token.Token
 }
 
@@ -171,6 +232,7 @@ func (continueStatement *ContinueStatement) TokenLiteral() string {
 func (continueStatement *ContinueStatement) String() string { return "continue" }
 
 type TryStatement struct {
+	Spanned
 	Token   token.Token
 	Try     *BlockStatement
 	Catch   *BlockStatement
@@ -195,7 +257,99 @@ func (tryStatement *TryStatement) String() string {
 	return str.String()
 }
 
+type YieldStatement struct {
+	Spanned
+	Token token.Token
+	Value Expression
+}
+
+func (yieldStatement *YieldStatement) statementNode()       {}
+func (yieldStatement *YieldStatement) TokenLiteral() string { return yieldStatement.Token.Literal }
+func (yieldStatement *YieldStatement) String() string {
+	var str strings.Builder
+	str.WriteString(yieldStatement.TokenLiteral())
+	str.WriteString(" ")
+	if yieldStatement.Value != nil {
+		str.WriteString(yieldStatement.Value.String())
+	}
+	return str.String()
+}
+
+type ImportStatement struct {
+	Spanned
+	Token token.Token
+	Path  *StringLiteral
+	Alias *Identifier
+}
+
+func (importStatement *ImportStatement) statementNode()       {}
+func (importStatement *ImportStatement) TokenLiteral() string { return importStatement.Token.Literal }
+func (importStatement *ImportStatement) String() string {
+	var str strings.Builder
+	str.WriteString("import \"")
+	str.WriteString(importStatement.Path.String())
+	str.WriteString("\"")
+	if importStatement.Alias != nil {
+		str.WriteString(" as ")
+		str.WriteString(importStatement.Alias.String())
+	}
+	return str.String()
+}
+
+type ExportStatement struct {
+	Spanned
+	Token     token.Token
+	Statement *LetStatement
+}
+
+func (exportStatement *ExportStatement) statementNode()       {}
+func (exportStatement *ExportStatement) TokenLiteral() string { return exportStatement.Token.Literal }
+func (exportStatement *ExportStatement) String() string {
+	return "export " + exportStatement.Statement.String()
+}
+
+type DeferStatement struct {
+	Spanned
+	Token      token.Token
+	Expression Expression
+}
+
+func (deferStatement *DeferStatement) statementNode()       {}
+func (deferStatement *DeferStatement) TokenLiteral() string { return deferStatement.Token.Literal }
+func (deferStatement *DeferStatement) String() string {
+	var str strings.Builder
+	str.WriteString(deferStatement.TokenLiteral())
+	str.WriteString(" ")
+	if deferStatement.Expression != nil {
+		str.WriteString(deferStatement.Expression.String())
+	}
+	return str.String()
+}
+
+type WithStatement struct {
+	Spanned
+	Token    token.Token
+	Resource Expression
+	Name     *Identifier
+	Body     *BlockStatement
+}
+
+func (withStatement *WithStatement) statementNode()       {}
+func (withStatement *WithStatement) TokenLiteral() string { return withStatement.Token.Literal }
+func (withStatement *WithStatement) String() string {
+	var str strings.Builder
+	str.WriteString(withStatement.TokenLiteral())
+	str.WriteString(" ")
+	str.WriteString(withStatement.Resource.String())
+	str.WriteString(" as ")
+	str.WriteString(withStatement.Name.String())
+	str.WriteString(" ")
+	str.WriteString(withStatement.Body.String())
+	return str.String()
+}
+
 type PrefixExpression struct {
+	Spanned
 	Token    token.Token
 	Operator string
 	Right    Expression
@@ -212,7 +366,23 @@ func (prefixExpression *PrefixExpression) String() string {
 	return str.String()
 }
 
+type PostfixExpression struct {
+	Spanned
+	Token    token.Token
+	Variable *Identifier
+	Operator string
+}
+
+func (postfixExpression *PostfixExpression) expressionNode() {}
+func (postfixExpression *PostfixExpression) TokenLiteral() string {
+	return postfixExpression.Token.Literal
+}
+func (postfixExpression *PostfixExpression) String() string {
+	return postfixExpression.Variable.String() + postfixExpression.Operator
+}
+
 type InfixExpression struct {
+	Spanned
 	Token    token.Token
 	Left     Expression
 	Operator string
@@ -231,9 +401,34 @@ func (infixExpression *InfixExpression) String() string {
 	return str.String()
 }
 
+type ComparisonExpression struct {
+	Spanned
+	Token     token.Token
+	Operands  []Expression
+	Operators []string
+}
+
+func (comparisonExpression *ComparisonExpression) expressionNode() {}
+func (comparisonExpression *ComparisonExpression) TokenLiteral() string {
+	return comparisonExpression.Token.Literal
+}
+func (comparisonExpression *ComparisonExpression) String() string {
+	var str strings.Builder
+	str.WriteString(comparisonExpression.Operands[0].String())
+	for index, operator := range comparisonExpression.Operators {
+		str.WriteString(" ")
+		str.WriteString(operator)
+		str.WriteString(" ")
+		str.WriteString(comparisonExpression.Operands[index+1].String())
+	}
+	return str.String()
+}
+
 type AssignExpression struct {
+	Spanned
 	Token    token.Token
 	Variable *Identifier
+	Slice    *SliceExpression
 	Value    Expression
 }
 
@@ -243,16 +438,22 @@ func (assignExpression *AssignExpression) TokenLiteral() string {
 }
 func (assignExpression *AssignExpression) String() string {
 	var str strings.Builder
-	str.WriteString(assignExpression.Variable.String())
+	if assignExpression.Slice != nil {
+		str.WriteString(assignExpression.Slice.String())
+	} else {
+		str.WriteString(assignExpression.Variable.String())
+	}
 	str.WriteString(" = ")
 	str.WriteString(assignExpression.Value.String())
 	return str.String()
 }
 
 type IndexExpression struct {
-	Token token.Token
-	Array Expression
-	Index Expression
+	Spanned
+	Token    token.Token
+	Array    Expression
+	Index    Expression
+	Optional bool
 }
 
 func (indexExpression *IndexExpression) expressionNode()      {}
@@ -260,13 +461,79 @@ func (indexExpression *IndexExpression) TokenLiteral() string { return indexExpr
 func (indexExpression *IndexExpression) String() string {
 	var str strings.Builder
 	str.WriteString(indexExpression.Array.String())
-	str.WriteString("[")
+	str.WriteString(indexExpression.Token.Literal)
 	str.WriteString(indexExpression.Index.String())
 	str.WriteString("]")
 	return str.String()
 }
 
+type SliceExpression struct {
+	Spanned
+	Token    token.Token
+	Left     Expression
+	Start    Expression
+	End      Expression
+	Step     Expression
+	Optional bool
+}
+
+func (sliceExpression *SliceExpression) expressionNode()      {}
+func (sliceExpression *SliceExpression) TokenLiteral() string { return sliceExpression.Token.Literal }
+func (sliceExpression *SliceExpression) String() string {
+	var str strings.Builder
+	str.WriteString(sliceExpression.Left.String())
+	str.WriteString(sliceExpression.Token.Literal)
+	if sliceExpression.Start != nil {
+		str.WriteString(sliceExpression.Start.String())
+	}
+	str.WriteString(":")
+	if sliceExpression.End != nil {
+		str.WriteString(sliceExpression.End.String())
+	}
+	if sliceExpression.Step != nil {
+		str.WriteString(":")
+		str.WriteString(sliceExpression.Step.String())
+	}
+	str.WriteString("]")
+	return str.String()
+}
+
+type SpreadExpression struct {
+	Spanned
+	Token token.Token
+	Value Expression
+}
+
+func (spreadExpression *SpreadExpression) expressionNode() {}
+func (spreadExpression *SpreadExpression) TokenLiteral() string {
+	return spreadExpression.Token.Literal
+}
+func (spreadExpression *SpreadExpression) String() string {
+	return "..." + spreadExpression.Value.String()
+}
+
+type MemberExpression struct {
+	Spanned
+	Token    token.Token
+	Object   Expression
+	Property *Identifier
+	Optional bool
+}
+
+func (memberExpression *MemberExpression) expressionNode() {}
+func (memberExpression *MemberExpression) TokenLiteral() string {
+	return memberExpression.Token.Literal
+}
+func (memberExpression *MemberExpression) String() string {
+	var str strings.Builder
+	str.WriteString(memberExpression.Object.String())
+	str.WriteString(memberExpression.Token.Literal)
+	str.WriteString(memberExpression.Property.String())
+	return str.String()
+}
+
 type IfExpression struct {
+	Spanned
 	Token       token.Token
 	Condition   Expression
 	Consequence *BlockStatement
@@ -289,7 +556,82 @@ func (ifExpression *IfExpression) String() string {
 	return str.String()
 }
 
+type ComprehensionExpression struct {
+	Spanned
+	Token    token.Token
+	Key      Expression
+	Value    Expression
+	Element  *Identifier
+	Iterator Expression
+	Guard    Expression
+}
+
+func (comprehensionExpression *ComprehensionExpression) expressionNode() {}
+func (comprehensionExpression *ComprehensionExpression) TokenLiteral() string {
+	return comprehensionExpression.Token.Literal
+}
+func (comprehensionExpression *ComprehensionExpression) String() string {
+	var str strings.Builder
+	if comprehensionExpression.Key != nil {
+		str.WriteString("{")
+		str.WriteString(comprehensionExpression.Key.String())
+		str.WriteString(": ")
+	} else {
+		str.WriteString("[")
+	}
+	str.WriteString(comprehensionExpression.Value.String())
+	str.WriteString(" for ")
+	str.WriteString(comprehensionExpression.Element.String())
+	str.WriteString(" in ")
+	str.WriteString(comprehensionExpression.Iterator.String())
+	if comprehensionExpression.Guard != nil {
+		str.WriteString(" if ")
+		str.WriteString(comprehensionExpression.Guard.String())
+	}
+	if comprehensionExpression.Key != nil {
+		str.WriteString("}")
+	} else {
+		str.WriteString("]")
+	}
+	return str.String()
+}
+
+type MatchArm struct {
+	Pattern Expression
+	Guard   Expression
+	Body    *BlockStatement
+}
+
+type MatchExpression struct {
+	Spanned
+	Token   token.Token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+func (matchExpression *MatchExpression) expressionNode()      {}
+func (matchExpression *MatchExpression) TokenLiteral() string { return matchExpression.Token.Literal }
+func (matchExpression *MatchExpression) String() string {
+	var str strings.Builder
+	str.WriteString("match ")
+	str.WriteString(matchExpression.Subject.String())
+	str.WriteString(" {")
+	for _, arm := range matchExpression.Arms {
+		str.WriteString("\n")
+		str.WriteString(arm.Pattern.String())
+		if arm.Guard != nil {
+			str.WriteString(" if ")
+			str.WriteString(arm.Guard.String())
+		}
+		str.WriteString(" => ")
+		str.WriteString(arm.Body.String())
+	}
+	str.WriteString("\n}")
+	return str.String()
+}
+
 type CallExpression struct {
+	Spanned
 	Token     token.Token
 	Function  Expression
 	Arguments []Expression
@@ -311,6 +653,7 @@ func (callExpression *CallExpression) String() string {
 }
 
 type Identifier struct {
+	Spanned
 	Token token.Token
 	Value string
 }
@@ -320,6 +663,7 @@ func (identifier *Identifier) TokenLiteral() string { return identifier.Token.Li
 func (identifier *Identifier) String() string       { return identifier.Value }
 
 type IntegerLiteral struct {
+	Spanned
 	Token token.Token
 	Value int
 }
@@ -329,6 +673,7 @@ func (integerLiteral *IntegerLiteral) TokenLiteral() string { return integerLite
 func (integerLiteral *IntegerLiteral) String() string       { return integerLiteral.TokenLiteral() }
 
 type FloatLiteral struct {
+	Spanned
 	Token token.Token
 	Value float64
 }
@@ -338,6 +683,7 @@ func (floatLiteral *FloatLiteral) TokenLiteral() string { return floatLiteral.To
 func (floatLiteral *FloatLiteral) String() string       { return floatLiteral.TokenLiteral() }
 
 type BooleanLiteral struct {
+	Spanned
 	Token token.Token
 	Value bool
 }
@@ -347,6 +693,7 @@ func (booleanLiteral *BooleanLiteral) TokenLiteral() string { return booleanLite
 func (booleanLiteral *BooleanLiteral) String() string       { return booleanLiteral.TokenLiteral() }
 
 type StringLiteral struct {
+	Spanned
 	Token token.Token
 	Value string
 }
@@ -356,6 +703,7 @@ func (stringLiteral *StringLiteral) TokenLiteral() string { return stringLiteral
 func (stringLiteral *StringLiteral) String() string       { return stringLiteral.TokenLiteral() }
 
 type ArrayLiteral struct {
+	Spanned
 	Token    token.Token
 	Elements []Expression
 }
@@ -374,7 +722,31 @@ func (arrayLiteral *ArrayLiteral) String() string {
 	return str.String()
 }
 
+type TupleLiteral struct {
+	Spanned
+	Token    token.Token
+	Elements []Expression
+}
+
+func (tupleLiteral *TupleLiteral) expressionNode()      {}
+func (tupleLiteral *TupleLiteral) TokenLiteral() string { return tupleLiteral.Token.Literal }
+func (tupleLiteral *TupleLiteral) String() string {
+	var str strings.Builder
+	str.WriteString("(")
+	elements := []string{}
+	for _, element := range tupleLiteral.Elements {
+		elements = append(elements, element.String())
+	}
+	str.WriteString(strings.Join(elements, ", "))
+	if len(elements) == 1 {
+		str.WriteString(",")
+	}
+	str.WriteString(")")
+	return str.String()
+}
+
 type HashLiteral struct {
+	Spanned
 	Token token.Token
 	Pairs map[Expression]Expression
 }
@@ -394,8 +766,10 @@ func (hashLiteral *HashLiteral) String() string {
 }
 
 type FunctionLiteral struct {
+	Spanned
 	Token      token.Token
 	Name       string
+	Generator  bool
 	Parameters []*Identifier
 	Body       *BlockStatement
 }
@@ -404,8 +778,11 @@ func (functionLiteral *FunctionLiteral) expressionNode()      {}
 func (functionLiteral *FunctionLiteral) TokenLiteral() string { return functionLiteral.Token.Literal }
 func (functionLiteral *FunctionLiteral) String() string {
 	var str strings.Builder
-	str.WriteString(functionLiteral.Name)
-	str.WriteString("fn(")
+	str.WriteString("fn")
+	if functionLiteral.Name != "" {
+		str.WriteString(" " + functionLiteral.Name)
+	}
+	str.WriteString("(")
 	parameters := []string{}
 	for _, parameter := range functionLiteral.Parameters {
 		parameters = append(parameters, parameter.String())
@@ -0,0 +1,167 @@
+package ast
+
+import "sort"
+
+// Traverses the AST depth-first, starting from the node
+// visit is called with every node before its children. If it returns false, then the children of that node are skipped
+// Children are visited in the order they appear in the source code
+// Example: Walk(program, func(node Node) bool { _, isFunction := node.(*FunctionLiteral); return !isFunction })
+func Walk(node Node, visit func(node Node) bool) {
+	if node == nil || !visit(node) {
+		return
+	}
+	for _, child := range Children(node) {
+		Walk(child, visit)
+	}
+}
+
+// Returns the child nodes of the node in source order. Missing optional parts are left out
+// Patterns, guards and bodies of match arms are children of the match expression
+func Children(node Node) []Node {
+	children := []Node{}
+	add := func(nodes ...Node) {
+		for _, child := range nodes {
+			if child != nil {
+				children = append(children, child)
+			}
+		}
+	}
+	switch node := node.(type) {
+	case *Program:
+		for _, statement := range node.Statements {
+			add(statement)
+		}
+	case *BlockStatement:
+		for _, statement := range node.Statements {
+			add(statement)
+		}
+	case *LetStatement:
+		add(node.Name, node.Value)
+	case *ReturnStatement:
+		add(node.ReturnValue)
+	case *ExpressionStatement:
+		add(node.Expression)
+	case *ForStatement:
+		add(node.Element, node.Iterator, node.Guard)
+		if node.Body != nil {
+			add(node.Body)
+		}
+	case *WhileStatement:
+		add(node.Condition)
+		if node.Body != nil {
+			add(node.Body)
+		}
+	case *TryStatement:
+		if node.Try != nil {
+			add(node.Try)
+		}
+		if node.Error != nil {
+			add(node.Error)
+		}
+		if node.Catch != nil {
+			add(node.Catch)
+		}
+		if node.Finally != nil {
+			add(node.Finally)
+		}
+	case *YieldStatement:
+		add(node.Value)
+	case *ImportStatement:
+		if node.Path != nil {
+			add(node.Path)
+		}
+		if node.Alias != nil {
+			add(node.Alias)
+		}
+	case *ExportStatement:
+		if node.Statement != nil {
+			add(node.Statement)
+		}
+	case *DeferStatement:
+		add(node.Expression)
+	case *WithStatement:
+		add(node.Resource)
+		if node.Name != nil {
+			add(node.Name)
+		}
+		if node.Body != nil {
+			add(node.Body)
+		}
+	case *PrefixExpression:
+		add(node.Right)
+	case *PostfixExpression:
+		add(node.Variable)
+	case *InfixExpression:
+		add(node.Left, node.Right)
+	case *ComparisonExpression:
+		for _, operand := range node.Operands {
+			add(operand)
+		}
+	case *AssignExpression:
+		if node.Slice != nil {
+			add(node.Slice)
+		} else {
+			add(node.Variable)
+		}
+		add(node.Value)
+	case *IndexExpression:
+		add(node.Array, node.Index)
+	case *SliceExpression:
+		add(node.Left, node.Start, node.End, node.Step)
+	case *SpreadExpression:
+		add(node.Value)
+	case *MemberExpression:
+		add(node.Object, node.Property)
+	case *IfExpression:
+		add(node.Condition)
+		if node.Consequence != nil {
+			add(node.Consequence)
+		}
+		if node.Alternate != nil {
+			add(node.Alternate)
+		}
+	case *ComprehensionExpression:
+		add(node.Key, node.Value, node.Element, node.Iterator, node.Guard)
+	case *MatchExpression:
+		add(node.Subject)
+		for _, arm := range node.Arms {
+			add(arm.Pattern, arm.Guard)
+			if arm.Body != nil {
+				add(arm.Body)
+			}
+		}
+	case *CallExpression:
+		add(node.Function)
+		for _, argument := range node.Arguments {
+			add(argument)
+		}
+	case *ArrayLiteral:
+		for _, element := range node.Elements {
+			add(element)
+		}
+	case *TupleLiteral:
+		for _, element := range node.Elements {
+			add(element)
+		}
+	case *HashLiteral:
+		keys := []Expression{}
+		for key := range node.Pairs {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			left, right := keys[i].Span().Start, keys[j].Span().Start
+			return left.Line < right.Line || (left.Line == right.Line && left.Column < right.Column)
+		})
+		for _, key := range keys {
+			add(key, node.Pairs[key])
+		}
+	case *FunctionLiteral:
+		for _, parameter := range node.Parameters {
+			add(parameter)
+		}
+		if node.Body != nil {
+			add(node.Body)
+		}
+	}
+	return children
+}
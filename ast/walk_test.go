@@ -0,0 +1,60 @@
+package ast_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mochatek/frolang/ast"
+	"github.com/mochatek/frolang/lexer"
+	"github.com/mochatek/frolang/parser"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+	par := parser.New(lexer.New(input))
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		t.Fatalf("Parse errors for %q: %v", input, par.Errors())
+	}
+	return program
+}
+
+func TestWalk(t *testing.T) {
+	program := parse(t, `let add = fn(a, b) { if a > 0 { return a + b } }
+for x in [1, 2] { add(x, {"k": x}) }`)
+	visited := []string{}
+	ast.Walk(program, func(node ast.Node) bool {
+		visited = append(visited, fmt.Sprintf("%T", node))
+		return true
+	})
+	expected := []string{
+		"*ast.Program",
+		"*ast.LetStatement", "*ast.Identifier", "*ast.FunctionLiteral", "*ast.Identifier", "*ast.Identifier",
+		"*ast.BlockStatement", "*ast.ExpressionStatement", "*ast.IfExpression", "*ast.InfixExpression",
+		"*ast.Identifier", "*ast.IntegerLiteral", "*ast.BlockStatement", "*ast.ReturnStatement",
+		"*ast.InfixExpression", "*ast.Identifier", "*ast.Identifier",
+		"*ast.ForStatement", "*ast.Identifier", "*ast.ArrayLiteral", "*ast.IntegerLiteral", "*ast.IntegerLiteral",
+		"*ast.BlockStatement", "*ast.ExpressionStatement", "*ast.CallExpression", "*ast.Identifier",
+		"*ast.Identifier", "*ast.HashLiteral", "*ast.StringLiteral", "*ast.Identifier",
+	}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("Expected %v, got %v", expected, visited)
+	}
+}
+
+// Returning false from visit skips the children of the node
+func TestWalkSkipsChildren(t *testing.T) {
+	program := parse(t, `let f = fn() { inner }; outer`)
+	identifiers := []string{}
+	ast.Walk(program, func(node ast.Node) bool {
+		if identifier, ok := node.(*ast.Identifier); ok {
+			identifiers = append(identifiers, identifier.Value)
+		}
+		_, isFunction := node.(*ast.FunctionLiteral)
+		return !isFunction
+	})
+	if expected := []string{"f", "outer"}; !reflect.DeepEqual(identifiers, expected) {
+		t.Errorf("Expected %v, got %v", expected, identifiers)
+	}
+}
@@ -2,34 +2,139 @@ package evaluator
 
 import (
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"math/bits"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/mochatek/frolang/object"
+	"github.com/mochatek/frolang/token"
 )
 
-const RESET = "\033[0m"
-const GREEN = "\033[32m"
+// Writers to which print and eprint/log builtins write
+// Embedders can replace them to capture the output
+var (
+	Stdout io.Writer = os.Stdout
+	Stderr io.Writer = os.Stderr
+)
+
+// Prefixes for the supported log levels
+var logLevels = map[string]string{
+	"debug": "[DEBUG]",
+	"info":  "[INFO]",
+	"warn":  "[WARN]",
+	"error": "[ERROR]",
+}
 
 // Separate Dictionary to support builtin methods
-var builtins = map[string]object.Object{
-	"print":    &object.Builtin{Fn: print},
-	"type":     &object.Builtin{Fn: typeOf},
-	"str":      &object.Builtin{Fn: str},
-	"len":      &object.Builtin{Fn: length},
-	"reversed": &object.Builtin{Fn: reversed},
-	"slice":    &object.Builtin{Fn: slice},
-	"range":    &object.Builtin{Fn: rangeOf},
-	"lower":    &object.Builtin{Fn: lower},
-	"upper":    &object.Builtin{Fn: upper},
-	"split":    &object.Builtin{Fn: split},
-	"join":     &object.Builtin{Fn: join},
-	"push":     &object.Builtin{Fn: push},
-	"pop":      &object.Builtin{Fn: pop},
-	"unshift":  &object.Builtin{Fn: unShift},
-	"shift":    &object.Builtin{Fn: shift},
-	"keys":     &object.Builtin{Fn: keys},
-	"values":   &object.Builtin{Fn: values},
-	"delete":   &object.Builtin{Fn: delete},
+// It is the only registry of builtins, including the scope builtins like globals and locals
+var builtins map[string]object.Object
+
+// Builtins like map call back into the evaluator, which looks up the builtins
+// So the registry is assigned on init, as referring to it on declaration is an initialization cycle
+func init() {
+	builtins = map[string]object.Object{
+		"print":       &object.Builtin{Fn: print},
+		"eprint":      &object.Builtin{Fn: eprint},
+		"log":         &object.Builtin{Fn: logTo},
+		"type":        &object.Builtin{Fn: typeOf},
+		"str":         &object.Builtin{Fn: str},
+		"repr":        &object.Builtin{Fn: repr},
+		"callable":    &object.Builtin{Fn: callable},
+		"arity":       &object.Builtin{Fn: arity},
+		"hash":        &object.Builtin{Fn: hashOf},
+		"deepEqual":   &object.Builtin{Fn: deepEqual},
+		"thaw":        &object.Builtin{Fn: thaw},
+		"isArray":     &object.Builtin{Fn: typePredicate(object.ARRAY_OBJ)},
+		"isString":    &object.Builtin{Fn: typePredicate(object.STRING_OBJ)},
+		"isNumber":    &object.Builtin{Fn: typePredicate(object.INTEGER_OBJ, object.FLOAT_OBJ)},
+		"isHash":      &object.Builtin{Fn: typePredicate(object.HASH_OBJ)},
+		"isNull":      &object.Builtin{Fn: typePredicate(object.NULL_OBJ)},
+		"isFunction":  &object.Builtin{Fn: typePredicate(object.FUNCTION_OBJ, object.BUILTIN_OBJ)},
+		"len":         &object.Builtin{Fn: length},
+		"reversed":    &object.Builtin{Fn: reversed},
+		"slice":       &object.Builtin{Fn: slice},
+		"take":        &object.Builtin{Fn: take},
+		"drop":        &object.Builtin{Fn: drop},
+		"chunk":       &object.Builtin{Fn: chunk},
+		"windows":     &object.Builtin{Fn: windows},
+		"range":       &object.Builtin{Fn: rangeOf},
+		"toFixed":     &object.Builtin{Fn: toFixed},
+		"toPrecision": &object.Builtin{Fn: toPrecision},
+		"clamp":       &object.Builtin{Fn: clamp},
+		"gcd":         &object.Builtin{Fn: gcd},
+		"lcm":         &object.Builtin{Fn: lcm},
+		"bitCount":    &object.Builtin{Fn: bitCount},
+		"shiftLeft":   &object.Builtin{Fn: bitShift("shiftLeft")},
+		"shiftRight":  &object.Builtin{Fn: bitShift("shiftRight")},
+		"lower":       &object.Builtin{Fn: lower},
+		"upper":       &object.Builtin{Fn: upper},
+		"split":       &object.Builtin{Fn: split},
+		"join":        &object.Builtin{Fn: join},
+		"padStart":    &object.Builtin{Fn: padStart},
+		"padEnd":      &object.Builtin{Fn: padEnd},
+		"startsWith":  &object.Builtin{Fn: affixMatcher("startsWith", strings.HasPrefix)},
+		"endsWith":    &object.Builtin{Fn: affixMatcher("endsWith", strings.HasSuffix)},
+		"lines":       &object.Builtin{Fn: splitter("lines", splitLines)},
+		"words":       &object.Builtin{Fn: splitter("words", strings.Fields)},
+		"strip":       &object.Builtin{Fn: trimmer("strip")},
+		"push":        &object.Builtin{Fn: push},
+		"pop":         &object.Builtin{Fn: pop},
+		"unshift":     &object.Builtin{Fn: unShift},
+		"shift":       &object.Builtin{Fn: shift},
+		"swap":        &object.Builtin{Fn: swap},
+		"fill":        &object.Builtin{Fn: fill},
+		"resize":      &object.Builtin{Fn: resize},
+		"delete":      &object.Builtin{Fn: delete},
+		"open":        &object.Builtin{Fn: open},
+		"color":       &object.Builtin{Fn: color},
+		"useColor":    &object.Builtin{Fn: useColor},
+		"apply":       &object.Builtin{Fn: apply},
+		"maxBy":       &object.Builtin{Fn: extremeBy("maxBy", token.GT)},
+		"minBy":       &object.Builtin{Fn: extremeBy("minBy", token.LT)},
+		"max":         &object.Builtin{Fn: extreme("max", token.GT)},
+		"min":         &object.Builtin{Fn: extreme("min", token.LT)},
+		"takeWhile":   &object.Builtin{Fn: takeWhile},
+		"dropWhile":   &object.Builtin{Fn: dropWhile},
+		"scan":        &object.Builtin{Fn: scan},
+		"flatMap":     &object.Builtin{Fn: flatMap},
+		"map":         &object.Builtin{Fn: mapArray},
+		"filter":      &object.Builtin{Fn: filterArray},
+		"reduce":      &object.Builtin{Fn: reduceArray},
+		"keys":        &object.Builtin{Fn: keys},
+		"values":      &object.Builtin{Fn: values},
+		"sort":        &object.Builtin{Fn: sorted},
+		"sorted":      &object.Builtin{Fn: sorted},
+		"spawn":       &object.Builtin{Fn: spawn},
+		"wait":        &object.Builtin{Fn: wait},
+		"chan":        &object.Builtin{Fn: makeChannel},
+		"send":        &object.Builtin{Fn: send},
+		"recv":        &object.Builtin{Fn: recv},
+		"close":       &object.Builtin{Fn: closeChannel},
+		"next":        &object.Builtin{Fn: next},
+		"globals":     scopeFunction(globals),
+		"locals":      scopeFunction(locals),
+	}
+}
+
+// Checks whether the name refers to a builtin function
+func IsBuiltin(name string) bool {
+	_, ok := builtins[name]
+	return ok
+}
+
+// Returns the names of all the builtin functions in sorted order
+func BuiltinNames() []string {
+	names := []string{}
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // Print arguments to stdOut
@@ -38,11 +143,44 @@ func print(arguments ...object.Object) object.Object {
 	for _, argument := range arguments {
 		items = append(items, argument.Inspect())
 	}
-	fmt.Println(GREEN, strings.Join(items, " "), RESET)
+	fmt.Fprintln(Stdout, strings.Join(items, " "))
+	return nil
+}
+
+// Print arguments to stdErr
+func eprint(arguments ...object.Object) object.Object {
+	items := []string{}
+	for _, argument := range arguments {
+		items = append(items, argument.Inspect())
+	}
+	fmt.Fprintln(Stderr, strings.Join(items, " "))
+	return nil
+}
+
+// Print arguments to stdErr, prefixed with the log level
+func logTo(arguments ...object.Object) object.Object {
+	if len(arguments) < 1 {
+		return newError("Wrong number of arguments. Got=%d want=minimum 1", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ {
+		return newError("Log level must be STRING. Got %s", arguments[0].Type())
+	}
+	level := arguments[0].(*object.String).Value
+	prefix, ok := logLevels[strings.ToLower(level)]
+	if !ok {
+		return newError("Log level must be one of debug, info, warn, error. Got %s", level)
+	}
+	items := []string{prefix}
+	for _, argument := range arguments[1:] {
+		items = append(items, argument.Inspect())
+	}
+	fmt.Fprintln(Stderr, strings.Join(items, " "))
 	return nil
 }
 
 // Returns the type of an identifier
+// Functions are FUNCTION, whereas builtin functions are BUILTIN
+// Values of expressions which did not result in a value (like call to print) are NULL
 func typeOf(arguments ...object.Object) object.Object {
 	if len(arguments) != 1 {
 		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
@@ -50,6 +188,21 @@ func typeOf(arguments ...object.Object) object.Object {
 	return &object.String{Value: string(arguments[0].Type())}
 }
 
+// Creates a builtin function, which returns whether the type of its argument is one of the supplied types
+func typePredicate(types ...object.ObjectType) func(arguments ...object.Object) object.Object {
+	return func(arguments ...object.Object) object.Object {
+		if len(arguments) != 1 {
+			return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+		}
+		for _, objectType := range types {
+			if arguments[0].Type() == objectType {
+				return TRUE
+			}
+		}
+		return FALSE
+	}
+}
+
 // Returns the stringified form of any value
 func str(arguments ...object.Object) object.Object {
 	if len(arguments) != 1 {
@@ -58,6 +211,54 @@ func str(arguments ...object.Object) object.Object {
 	return &object.String{Value: arguments[0].Inspect()}
 }
 
+// Returns the unambiguous representation of any value, where strings are quoted
+func repr(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	return &object.String{Value: object.Repr(arguments[0])}
+}
+
+// Returns whether the argument can be called as a function
+func callable(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	switch arguments[0].(type) {
+	case *object.Function, *object.Builtin:
+		return TRUE
+	}
+	return FALSE
+}
+
+// Returns the number of parameters of a function
+// Builtin functions are variadic, so -1 is returned for them
+func arity(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	switch function := arguments[0].(type) {
+	case *object.Function:
+		return &object.Integer{Value: len(function.Parameters)}
+	case *object.Builtin:
+		return &object.Integer{Value: -1}
+	}
+	return newError("Argument to arity must be FUNCTION or BUILTIN. Got %s", arguments[0].Type())
+}
+
+// Returns a stable 64-bit integer hash of a hashable value
+// Equal values have the same hash. ie, hash(1) == hash(1.0)
+func hashOf(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	value, ok := object.HashOf(arguments[0])
+	if !ok {
+		return newError("Key: %s cannot be hashed", arguments[0].Type())
+	}
+	return &object.Integer{Value: int(value)}
+}
+
 // Returns the length of an iterable
 func length(arguments ...object.Object) object.Object {
 	if len(arguments) != 1 {
@@ -68,6 +269,8 @@ func length(arguments ...object.Object) object.Object {
 		return &object.Integer{Value: len(arg.Value)}
 	case *object.Array:
 		return &object.Integer{Value: len(arg.Elements)}
+	case *object.Tuple:
+		return &object.Integer{Value: len(arg.Elements)}
 	case *object.Hash:
 		return &object.Integer{Value: len(arg.Pairs)}
 	default:
@@ -75,6 +278,57 @@ func length(arguments ...object.Object) object.Object {
 	}
 }
 
+// Returns whether the two values are structurally equal
+// Unlike ==, arrays and hashes are compared by their contents instead of reference
+func deepEqual(arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	return nativeToBooleanObject(objectsDeepEqual(arguments[0], arguments[1], map[[2]object.Object]bool{}))
+}
+
+// Returns a deep copy of the value, so that a modified version can be derived without touching the original
+// Arrays, tuples and hashes are copied at every level. Other values are returned as they are
+func thaw(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	return deepCopy(arguments[0], map[object.Object]object.Object{})
+}
+
+// Copies the containers of the value recursively
+// Copies are remembered, so that a container shared by the value is copied once and stays shared in the copy
+func deepCopy(obj object.Object, copies map[object.Object]object.Object) object.Object {
+	if copied, ok := copies[obj]; ok {
+		return copied
+	}
+	switch obj := obj.(type) {
+	case *object.Array:
+		array := &object.Array{Elements: make([]object.Object, len(obj.Elements), len(obj.Elements))}
+		copies[obj] = array
+		for idx, element := range obj.Elements {
+			array.Elements[idx] = deepCopy(element, copies)
+		}
+		return array
+	case *object.Tuple:
+		tuple := &object.Tuple{Elements: make([]object.Object, len(obj.Elements), len(obj.Elements))}
+		copies[obj] = tuple
+		for idx, element := range obj.Elements {
+			tuple.Elements[idx] = deepCopy(element, copies)
+		}
+		return tuple
+	case *object.Hash:
+		hash := &object.Hash{Pairs: make(map[object.HashKey]object.HashPair, len(obj.Pairs))}
+		copies[obj] = hash
+		for key, pair := range obj.Pairs {
+			hash.Pairs[key] = object.HashPair{Key: deepCopy(pair.Key, copies), Value: deepCopy(pair.Value, copies)}
+		}
+		return hash
+	default:
+		return obj
+	}
+}
+
 // Returns the reversed form of an array/string
 func reversed(arguments ...object.Object) object.Object {
 	if len(arguments) != 1 {
@@ -131,6 +385,104 @@ func slice(arguments ...object.Object) object.Object {
 	return sliced
 }
 
+// Validates the arguments of take and drop
+// Returns the array and the count clamped between 0 and the length of the array
+func countArguments(name string, arguments []object.Object) (*object.Array, int, *object.Error) {
+	if len(arguments) != 2 {
+		return nil, 0, newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	array, ok := arguments[0].(*object.Array)
+	if !ok {
+		return nil, 0, newError("First argument to %s must be ARRAY. Got %s", name, arguments[0].Type())
+	}
+	count, ok := arguments[1].(*object.Integer)
+	if !ok {
+		return nil, 0, newError("Second argument to %s must be INTEGER. Got %s", name, arguments[1].Type())
+	}
+	n := min(count.Value, len(array.Elements))
+	if n < 0 {
+		n = 0
+	}
+	return array, n, nil
+}
+
+// Returns a new array with the first n elements of the array
+// Whole array is taken if n exceeds its length
+func take(arguments ...object.Object) object.Object {
+	array, n, err := countArguments("take", arguments)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, n)
+	copy(elements, array.Elements[:n])
+	return &object.Array{Elements: elements}
+}
+
+// Returns a new array without the first n elements of the array
+// Empty array is returned if n exceeds its length
+func drop(arguments ...object.Object) object.Object {
+	array, n, err := countArguments("drop", arguments)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, len(array.Elements)-n)
+	copy(elements, array.Elements[n:])
+	return &object.Array{Elements: elements}
+}
+
+// Validates the arguments of chunk and windows
+// Returns the array and the group size, which must be positive
+func groupArguments(name string, arguments []object.Object) (*object.Array, int, *object.Error) {
+	if len(arguments) != 2 {
+		return nil, 0, newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	array, ok := arguments[0].(*object.Array)
+	if !ok {
+		return nil, 0, newError("First argument to %s must be ARRAY. Got %s", name, arguments[0].Type())
+	}
+	size, ok := arguments[1].(*object.Integer)
+	if !ok {
+		return nil, 0, newError("Second argument to %s must be INTEGER. Got %s", name, arguments[1].Type())
+	}
+	if size.Value <= 0 {
+		return nil, 0, newError("Size for %s must be positive. Got %d", name, size.Value)
+	}
+	return array, size.Value, nil
+}
+
+// Splits the array into consecutive arrays of the given size
+// Last array will be smaller if the elements do not divide evenly
+func chunk(arguments ...object.Object) object.Object {
+	array, size, err := groupArguments("chunk", arguments)
+	if err != nil {
+		return err
+	}
+	chunks := []object.Object{}
+	for start := 0; start < len(array.Elements); start += size {
+		end := min(start+size, len(array.Elements))
+		elements := make([]object.Object, end-start)
+		copy(elements, array.Elements[start:end])
+		chunks = append(chunks, &object.Array{Elements: elements})
+	}
+	return &object.Array{Elements: chunks}
+}
+
+// Returns the overlapping arrays of the given size, sliding one element at a time
+// Returns an empty array if size exceeds the length of the array
+func windows(arguments ...object.Object) object.Object {
+	array, size, err := groupArguments("windows", arguments)
+	if err != nil {
+		return err
+	}
+	result := []object.Object{}
+	for start := 0; start+size <= len(array.Elements); start++ {
+		elements := make([]object.Object, size)
+		copy(elements, array.Elements[start:start+size])
+		result = append(result, &object.Array{Elements: elements})
+	}
+	return &object.Array{Elements: result}
+}
+
 // Returns an array of integers ranging from start and end values
 // End index is exclusive
 func rangeOf(arguments ...object.Object) object.Object {
@@ -153,6 +505,215 @@ func rangeOf(arguments ...object.Object) object.Object {
 	return &object.Array{Elements: elements}
 }
 
+// Validates the arguments of toFixed and toPrecision
+// Returns the number and the digit count, which must be between minimum and 100
+func digitArguments(name string, minimum int, arguments []object.Object) (float64, int, *object.Error) {
+	if len(arguments) != 2 {
+		return 0, 0, newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	number, ok := toFloat(arguments[0])
+	if !ok {
+		return 0, 0, newError("First argument to %s must be INTEGER/FLOAT. Got %s", name, arguments[0].Type())
+	}
+	digits, ok := arguments[1].(*object.Integer)
+	if !ok {
+		return 0, 0, newError("Second argument to %s must be INTEGER. Got %s", name, arguments[1].Type())
+	}
+	if digits.Value < minimum || digits.Value > 100 {
+		return 0, 0, newError("Digits for %s must be between %d and 100. Got %d", name, minimum, digits.Value)
+	}
+	return number, digits.Value, nil
+}
+
+// Returns the number as string with the given number of digits after the decimal point
+// Example: toFixed(3.14159, 2) gives "3.14"
+func toFixed(arguments ...object.Object) object.Object {
+	number, digits, err := digitArguments("toFixed", 0, arguments)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: strconv.FormatFloat(roundHalfUp(number, digits), 'f', digits, 64)}
+}
+
+// Returns the number as string with the given number of significant digits
+// Like JavaScript, exponential notation is used if the exponent is less than -6 or not less than the digits
+// Example: toPrecision(123.456, 4) gives "123.5", toPrecision(123.456, 2) gives "1.2e+2"
+func toPrecision(arguments ...object.Object) object.Object {
+	number, digits, err := digitArguments("toPrecision", 1, arguments)
+	if err != nil {
+		return err
+	}
+	_, exponentText, _ := strings.Cut(strconv.FormatFloat(number, 'e', digits-1, 64), "e")
+	exponent, _ := strconv.Atoi(exponentText)
+	number = roundHalfUp(number, digits-1-exponent)
+	mantissa, exponentText, _ := strings.Cut(strconv.FormatFloat(number, 'e', digits-1, 64), "e")
+	exponent, _ = strconv.Atoi(exponentText)
+	if exponent < -6 || exponent >= digits {
+		sign := "+"
+		if exponent < 0 {
+			sign, exponent = "-", -exponent
+		}
+		return &object.String{Value: fmt.Sprintf("%se%s%d", mantissa, sign, exponent)}
+	}
+	decimals := digits - 1 - exponent
+	if decimals < 0 {
+		decimals = 0
+	}
+	return &object.String{Value: strconv.FormatFloat(number, 'f', decimals, 64)}
+}
+
+// Returns the value limited to the range from low to high
+// Result is integer if all the arguments are integers, else float
+func clamp(arguments ...object.Object) object.Object {
+	if len(arguments) != 3 {
+		return newError("Wrong number of arguments. Got=%d want=3", len(arguments))
+	}
+	numbers := make([]float64, 3)
+	for index, argument := range arguments {
+		number, ok := toFloat(argument)
+		if !ok {
+			return newError("Arguments to clamp must be INTEGER/FLOAT. Got %s", argument.Type())
+		}
+		numbers[index] = number
+	}
+	value, low, high := numbers[0], numbers[1], numbers[2]
+	if low > high {
+		return newError("Need (low <= high) for clamp. Got low=%s high=%s", arguments[1].Inspect(), arguments[2].Inspect())
+	}
+	result := arguments[0]
+	if value < low {
+		result = arguments[1]
+	} else if value > high {
+		result = arguments[2]
+	}
+	if integer, ok := result.(*object.Integer); ok {
+		for _, argument := range arguments {
+			if argument.Type() == object.FLOAT_OBJ {
+				return &object.Float{Value: float64(integer.Value)}
+			}
+		}
+	}
+	return result
+}
+
+// Helper function to validate that an argument of the builtin is a function or builtin which can be called
+// Position tells which argument it is in the error message, like First or Second
+func checkCallable(name string, position string, argument object.Object) *object.Error {
+	switch argument.(type) {
+	case *object.Function, *object.Builtin:
+		return nil
+	}
+	return newError("%s argument to %s must be FUNCTION or BUILTIN. Got %s", position, name, argument.Type())
+}
+
+// Validates that the expected number of arguments are passed, and all of them are integers
+// Returns the values of the integers
+func integerArguments(name string, count int, arguments []object.Object) ([]int, *object.Error) {
+	if len(arguments) != count {
+		return nil, newError("Wrong number of arguments. Got=%d want=%d", len(arguments), count)
+	}
+	values := make([]int, count)
+	for index, argument := range arguments {
+		integer, ok := argument.(*object.Integer)
+		if !ok {
+			return nil, newError("Arguments to %s must be INTEGER. Got %s", name, argument.Type())
+		}
+		values[index] = integer.Value
+	}
+	return values, nil
+}
+
+// Returns the greatest common divisor of two integers using Euclidean algorithm
+// Result is never negative, and gcd(0, n) is the absolute value of n
+func gcd(arguments ...object.Object) object.Object {
+	values, err := integerArguments("gcd", 2, arguments)
+	if err != nil {
+		return err
+	}
+	return &object.Integer{Value: euclid(values[0], values[1])}
+}
+
+// Returns the least common multiple of two integers
+// Result is never negative, and it is 0 if any of the integers is 0
+func lcm(arguments ...object.Object) object.Object {
+	values, err := integerArguments("lcm", 2, arguments)
+	if err != nil {
+		return err
+	}
+	a, b := values[0], values[1]
+	if a == 0 || b == 0 {
+		return &object.Integer{Value: 0}
+	}
+	result := a / euclid(a, b) * b
+	if result < 0 {
+		result = -result
+	}
+	return &object.Integer{Value: result}
+}
+
+// Helper function to find the greatest common divisor
+func euclid(a int, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// Returns the number of 1 bits in the integer
+// Negative integers are counted in their 64-bit two's complement form. ie, bitCount(-1) is 64
+func bitCount(arguments ...object.Object) object.Object {
+	values, err := integerArguments("bitCount", 1, arguments)
+	if err != nil {
+		return err
+	}
+	return &object.Integer{Value: bits.OnesCount64(uint64(values[0]))}
+}
+
+// Creates a builtin that shifts the bits of an integer to the left/right by the given amount
+// Right shift keeps the sign of the integer
+func bitShift(name string) func(arguments ...object.Object) object.Object {
+	return func(arguments ...object.Object) object.Object {
+		values, err := integerArguments(name, 2, arguments)
+		if err != nil {
+			return err
+		}
+		number, amount := values[0], values[1]
+		if amount < 0 {
+			return newError("Shift amount for %s cannot be negative. Got %d", name, amount)
+		}
+		if name == "shiftLeft" {
+			return &object.Integer{Value: number << amount}
+		}
+		return &object.Integer{Value: number >> amount}
+	}
+}
+
+// Moves the number slightly away from zero, if it lies exactly halfway when rounded to the decimals
+// Go rounds such numbers to the even digit, whereas JavaScript rounds them up. ie, 2.5 is rounded to 3
+// Negative decimals round to the left of the decimal point
+func roundHalfUp(number float64, decimals int) float64 {
+	exponent := decimals
+	if exponent < 0 {
+		exponent = -exponent
+	}
+	power := new(big.Float).SetPrec(2048).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil))
+	scaled := new(big.Float).SetPrec(2048).SetFloat64(math.Abs(number))
+	if decimals < 0 {
+		scaled.Quo(scaled, power)
+	} else {
+		scaled.Mul(scaled, power)
+	}
+	integer, _ := scaled.Int(nil)
+	fraction := scaled.Sub(scaled, new(big.Float).SetInt(integer))
+	if fraction.Cmp(big.NewFloat(0.5)) == 0 {
+		return math.Nextafter(number, math.Copysign(math.Inf(1), number))
+	}
+	return number
+}
+
 // Returns the lower case form of a string
 func lower(arguments ...object.Object) object.Object {
 	if len(arguments) != 1 {
@@ -192,21 +753,36 @@ func split(arguments ...object.Object) object.Object {
 
 // Combine elements in an array to form a string and return it
 // Separating character will be comma, if not supplied
+// If deep is true, then nested arrays are flattened, so that their elements are joined too
 func join(arguments ...object.Object) object.Object {
-	if 1 > len(arguments) || len(arguments) > 2 {
-		return newError("Wrong number of arguments. Got=%d want=(min:1, max: 2)", len(arguments))
+	if 1 > len(arguments) || len(arguments) > 3 {
+		return newError("Wrong number of arguments. Got=%d want=(min:1, max: 3)", len(arguments))
 	}
 	if arguments[0].Type() != object.ARRAY_OBJ {
 		return newError("First argument to join must be ARRAY. Got %s", arguments[0].Type())
 	}
 	array := arguments[0].(*object.Array)
 	separator := ", "
-	if len(arguments) == 2 {
+	if len(arguments) >= 2 {
 		if arguments[1].Type() != object.STRING_OBJ {
-			return newError("Separator to join must be STRING. Got %s", arguments[0].Type())
+			return newError("Separator to join must be STRING. Got %s", arguments[1].Type())
 		}
 		separator = arguments[1].(*object.String).Value
 	}
+	deep := false
+	if len(arguments) == 3 {
+		if arguments[2].Type() != object.BOOLEAN_OBJ {
+			return newError("Third argument to join must be BOOLEAN. Got %s", arguments[2].Type())
+		}
+		deep = arguments[2] == TRUE
+	}
+	if deep {
+		stringArray, ok := flattenStrings(array, nil)
+		if !ok {
+			return newError("Cannot join an array which contains itself")
+		}
+		return &object.String{Value: strings.Join(stringArray, separator)}
+	}
 	stringArray := make([]string, len(array.Elements), len(array.Elements))
 	for idx, element := range array.Elements {
 		stringArray[idx] = element.Inspect()
@@ -214,6 +790,31 @@ func join(arguments ...object.Object) object.Object {
 	return &object.String{Value: strings.Join(stringArray, separator)}
 }
 
+// Returns the string form of the elements of nested arrays in order
+// parents are the arrays being flattened. Returns false if an array contains itself
+func flattenStrings(array *object.Array, parents []*object.Array) ([]string, bool) {
+	for _, parent := range parents {
+		if parent == array {
+			return nil, false
+		}
+	}
+	parents = append(parents, array)
+	stringArray := []string{}
+	for _, element := range array.Elements {
+		nested, ok := element.(*object.Array)
+		if !ok {
+			stringArray = append(stringArray, element.Inspect())
+			continue
+		}
+		nestedStrings, ok := flattenStrings(nested, parents)
+		if !ok {
+			return nil, false
+		}
+		stringArray = append(stringArray, nestedStrings...)
+	}
+	return stringArray, true
+}
+
 // Add elements to the end of an array and return it
 func push(arguments ...object.Object) object.Object {
 	if len(arguments) < 2 {
@@ -230,6 +831,77 @@ func push(arguments ...object.Object) object.Object {
 	return &object.Array{Elements: newElements}
 }
 
+// Exchange the elements at two indices of an array
+// As arrays are immutable, a new array with the elements exchanged is returned
+func swap(arguments ...object.Object) object.Object {
+	if len(arguments) != 3 {
+		return newError("Wrong number of arguments. Got=%d want=3", len(arguments))
+	}
+	if arguments[0].Type() != object.ARRAY_OBJ {
+		return newError("First argument to swap must be ARRAY. Got %s", arguments[0].Type())
+	}
+	array := arguments[0].(*object.Array)
+	indices := [2]int{}
+	for position, argument := range arguments[1:] {
+		index, ok := argument.(*object.Integer)
+		if !ok {
+			return newError("Indices to swap must be INTEGER. Got %s", argument.Type())
+		}
+		if index.Value < 0 || index.Value >= len(array.Elements) {
+			return newError("Index %d out of range for array of length %d", index.Value, len(array.Elements))
+		}
+		indices[position] = index.Value
+	}
+	newElements := make([]object.Object, len(array.Elements))
+	copy(newElements, array.Elements)
+	newElements[indices[0]], newElements[indices[1]] = newElements[indices[1]], newElements[indices[0]]
+	return &object.Array{Elements: newElements}
+}
+
+// Create an array of n copies of the value
+func fill(arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	size, ok := arguments[1].(*object.Integer)
+	if !ok {
+		return newError("Second argument to fill must be INTEGER. Got %s", arguments[1].Type())
+	}
+	if size.Value < 0 {
+		return newError("Size for fill cannot be negative. Got %d", size.Value)
+	}
+	newElements := make([]object.Object, size.Value)
+	for index := range newElements {
+		newElements[index] = arguments[0]
+	}
+	return &object.Array{Elements: newElements}
+}
+
+// Return a new array of length n from an array
+// Array is truncated if it is longer, or grown with the fill value if it is shorter
+func resize(arguments ...object.Object) object.Object {
+	if len(arguments) != 3 {
+		return newError("Wrong number of arguments. Got=%d want=3", len(arguments))
+	}
+	if arguments[0].Type() != object.ARRAY_OBJ {
+		return newError("First argument to resize must be ARRAY. Got %s", arguments[0].Type())
+	}
+	array := arguments[0].(*object.Array)
+	size, ok := arguments[1].(*object.Integer)
+	if !ok {
+		return newError("Second argument to resize must be INTEGER. Got %s", arguments[1].Type())
+	}
+	if size.Value < 0 {
+		return newError("Size for resize cannot be negative. Got %d", size.Value)
+	}
+	newElements := make([]object.Object, size.Value)
+	copied := copy(newElements, array.Elements)
+	for index := copied; index < size.Value; index++ {
+		newElements[index] = arguments[2]
+	}
+	return &object.Array{Elements: newElements}
+}
+
 // Remove last element from an array and return it
 func pop(arguments ...object.Object) object.Object {
 	if len(arguments) != 1 {
@@ -283,32 +955,39 @@ func shift(arguments ...object.Object) object.Object {
 }
 
 // Returns an array of keys in a hash
+// If sorted option is true, then the keys are in ascending order
 func keys(arguments ...object.Object) object.Object {
-	if len(arguments) != 1 {
-		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
-	}
-	if arguments[0].Type() != object.HASH_OBJ {
-		return newError("Argument to keys must be HASH. Got %s", arguments[0].Type())
-	}
-	hash := arguments[0].(*object.Hash)
-	array := hash.Iter()
-	return &array
+	return hashElements("keys", arguments, func(pair object.HashPair) object.Object { return pair.Key })
 }
 
 // Returns an array of values in a hash
+// If sorted option is true, then the values are in the ascending order of their keys
 func values(arguments ...object.Object) object.Object {
-	if len(arguments) != 1 {
-		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	return hashElements("values", arguments, func(pair object.HashPair) object.Object { return pair.Value })
+}
+
+// Returns an array of the elements picked from each pair of the hash
+func hashElements(name string, arguments []object.Object, pick func(pair object.HashPair) object.Object) object.Object {
+	if 1 > len(arguments) || len(arguments) > 2 {
+		return newError("Wrong number of arguments. Got=%d want=(min:1, max: 2)", len(arguments))
 	}
-	if arguments[0].Type() != object.HASH_OBJ {
-		return newError("Argument to values must be HASH. Got %s", arguments[0].Type())
+	hash, ok := arguments[0].(*object.Hash)
+	if !ok {
+		return newError("First argument to %s must be HASH. Got %s", name, arguments[0].Type())
 	}
-	hash := arguments[0].(*object.Hash)
-	array := object.Array{}
-	for _, pair := range hash.Pairs {
-		array.Elements = append(array.Elements, pair.Value)
+	options, err := booleanOptions(name, arguments[1:], "sorted")
+	if err != nil {
+		return err
 	}
-	return &array
+	pairs, err := hashPairs(name, hash, options["sorted"])
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, len(pairs), len(pairs))
+	for idx, pair := range pairs {
+		elements[idx] = pick(pair)
+	}
+	return &object.Array{Elements: elements}
 }
 
 // Removes a key-value pair form a hash and return it
@@ -320,10 +999,10 @@ func delete(arguments ...object.Object) object.Object {
 		return newError("First argument to delete must be HASH. Got %s", arguments[0].Type())
 	}
 	hash := arguments[0].(*object.Hash)
-	if deleteKey, ok := arguments[1].(object.Hashable); ok {
+	if deleteKey, ok := object.HashKeyOf(arguments[1]); ok {
 		newHashPairs := make(map[object.HashKey]object.HashPair)
 		for key, value := range hash.Pairs {
-			if key != deleteKey.HashKey() {
+			if key != deleteKey {
 				newHashPairs[key] = value
 			}
 		}
@@ -351,3 +1030,13 @@ func _len(iterable object.Iterable) int {
 	}
 	return length
 }
+
+// Helper function to create a hash object from string keys and their values
+func newStringKeyHash(values map[string]object.Object) *object.Hash {
+	pairs := make(map[object.HashKey]object.HashPair)
+	for key, value := range values {
+		keyObject := &object.String{Value: key}
+		pairs[keyObject.HashKey()] = object.HashPair{Key: keyObject, Value: value}
+	}
+	return &object.Hash{Pairs: pairs}
+}
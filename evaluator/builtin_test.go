@@ -0,0 +1,271 @@
+package evaluator
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Output of eprint and log goes to stderr, and output of print to stdout
+func TestErrorStream(t *testing.T) {
+	var stdout, stderr strings.Builder
+	Stdout, Stderr = &stdout, &stderr
+	defer func() { Stdout, Stderr = io.Discard, os.Stderr }()
+	testEval(t, `print("out"); eprint("err"); log("warn", "retry")`)
+	if !strings.Contains(stdout.String(), "out") || strings.Contains(stdout.String(), "err") {
+		t.Errorf("Expected only print output on stdout, got %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "err") || !strings.Contains(stderr.String(), "retry") {
+		t.Errorf("Expected eprint and log output on stderr, got %q", stderr.String())
+	}
+}
+
+func TestColor(t *testing.T) {
+	defer func(useColor bool) { UseColor = useColor }(UseColor)
+	UseColor = true
+	runEvalTests(t, []struct{ input, expected string }{
+		{`color("hi", "red")`, "\x1b[31mhi\x1b[0m"},
+		{`useColor(false); color("hi", "red")`, "hi"},
+		{`color("hi", "pink")`, "EVAL ERROR: Unknown color: pink"},
+	})
+
+	var stdout strings.Builder
+	Stdout = &stdout
+	defer func() { Stdout = io.Discard }()
+	UseColor = true
+	testEval(t, `print("plain")`)
+	if stdout.String() != "plain\n" {
+		t.Errorf("Expected print not to color its output, got %q", stdout.String())
+	}
+}
+
+func TestRepr(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[repr("1"), repr(1)]`, `["1", 1]`},
+		{`repr([1, "a"])`, `[1, "a"]`},
+		{`str([1, "a"])`, "[1, a]"},
+	})
+}
+
+func TestScopeIntrospection(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let x = 1; globals()["x"]`, "1"},
+		{`let x = 1; let f = fn(a) { let y = 2; locals() }; let scope = f(3); [scope["a"], scope["y"], scope["x"]]`, "[3, 2, null]"},
+		{`let x = 1; locals()["x"]`, "1"},
+	})
+}
+
+func TestCallableAndArity(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let f = fn(a, b) { a }; [callable(f), callable(len), callable(1), callable("len")]`, "[true, true, false, false]"},
+		{`[arity(fn(a, b) { a }), arity(fn() { 1 }), arity(len)]`, "[2, 0, -1]"},
+		{`arity(1)`, "EVAL ERROR: Argument to arity must be FUNCTION or BUILTIN. Got INTEGER"},
+	})
+}
+
+// Every kind of object has a type name, and errors are not hidden by type
+func TestTypeNames(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[type(1), type(1.5), type("a"), type(true), type(print())]`, "[INTEGER, FLOAT, STRING, BOOLEAN, NULL]"},
+		{`[type([]), type({}), type(len), type(fn() {})]`, "[ARRAY, HASH, BUILTIN, FUNCTION]"},
+		{`import "math" as m; [type(m), type(chan()), type(spawn(fn() { 1 })), type(fn() { yield 1 }())]`, "[MODULE, CHANNEL, TASK, GENERATOR]"},
+		{`let f = fn() { let x = 1 }; type(f())`, "NULL"},
+		{`type(1 / 0)`, "EVAL ERROR: Division by 0 is not allowed"},
+	})
+}
+
+func TestTypePredicates(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[isArray([]), isArray("a"), isString("a"), isString(1)]`, "[true, false, true, false]"},
+		{`[isNumber(1), isNumber(1.5), isNumber("1")]`, "[true, true, false]"},
+		{`[isHash({}), isHash([]), isNull(print()), isNull(0)]`, "[true, false, true, false]"},
+		{`[isFunction(len), isFunction(fn() {}), isFunction(1)]`, "[true, true, false]"},
+	})
+}
+
+func TestTakeAndDrop(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[take([1, 2, 3], 2), drop([1, 2, 3], 2)]`, "[[1, 2], [3]]"},
+		{`[take([1, 2], 5), drop([1, 2], 5)]`, "[[1, 2], []]"},
+		{`take([1], -1)`, "[]"},
+	})
+}
+
+func TestChunkAndWindows(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`chunk([1, 2, 3, 4, 5], 2)`, "[[1, 2], [3, 4], [5]]"},
+		{`windows([1, 2, 3, 4], 3)`, "[[1, 2, 3], [2, 3, 4]]"},
+		{`windows([1, 2], 3)`, "[]"},
+		{`chunk([1], 0)`, "EVAL ERROR: Size for chunk must be positive. Got 0"},
+	})
+}
+
+func TestSwap(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`swap([1, 2, 3], 0, 2)`, "[3, 2, 1]"},
+		{`let a = [1, 2]; let b = swap(a, 0, 1); [a, b]`, "[[1, 2], [2, 1]]"},
+		{`swap([1], 0, 5)`, "EVAL ERROR: Index 5 out of range for array of length 1"},
+	})
+}
+
+func TestFillAndResize(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`fill(0, 3)`, "[0, 0, 0]"},
+		{`resize([1, 2], 4, 0)`, "[1, 2, 0, 0]"},
+		{`resize([1, 2, 3], 1, 0)`, "[1]"},
+	})
+}
+
+func TestNumberFormatting(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`toFixed(3.14159, 2)`, "3.14"},
+		{`toFixed(2, 1)`, "2.0"},
+		{`toPrecision(1234.5, 2)`, "1.2e+3"},
+		{`type(toFixed(1.5, 0))`, "STRING"},
+	})
+}
+
+func TestClamp(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[clamp(-1, 0, 10), clamp(5, 0, 10), clamp(11, 0, 10)]`, "[0, 5, 10]"},
+		{`clamp(1.5, 0, 1)`, "1.00"},
+	})
+}
+
+func TestGcdAndLcm(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[gcd(8, 15), lcm(8, 15)]`, "[1, 120]"},
+		{`[gcd(12, 18), lcm(4, 6)]`, "[6, 12]"},
+		{`[gcd(0, 5), lcm(0, 5), gcd(-4, 6)]`, "[5, 0, 2]"},
+	})
+}
+
+func TestBitBuiltins(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[bitCount(255), bitCount(0), bitCount(5)]`, "[8, 0, 2]"},
+		{`[shiftLeft(1, 4), shiftRight(256, 4)]`, "[16, 16]"},
+	})
+}
+
+func TestHashBuiltin(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[hash([1, 2]) == hash([1, 2]), hash("a") == hash("a")]`, "[true, true]"},
+		{`[hash("a") == hash("b"), hash([1, 2]) == hash([2, 1])]`, "[false, false]"},
+		{`hash({})`, "EVAL ERROR: Key: HASH cannot be hashed"},
+	})
+}
+
+func TestPadding(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[padStart("5", 3, "ab"), padEnd("5", 4, "xy")]`, "[ab5, 5xyx]"},
+		{`[padStart("long", 2, "0"), padEnd("long", 4, "0")]`, "[long, long]"},
+		{`padStart("5", 3)`, "  5"},
+	})
+}
+
+func TestAffixes(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[startsWith("frolang", ["go", "fro"]), endsWith("frolang", ["x", "y"])]`, "[true, false]"},
+		{`[startsWith("fro", "f"), endsWith("fro", "ro"), startsWith("fro", [])]`, "[true, true, false]"},
+	})
+}
+
+func TestLinesAndWords(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{"lines(\"a\r\nb\nc\n\")", "[a, b, c]"},
+		{"lines(\"\")", "[]"},
+		{"words(\"  a   b\tc \")", "[a, b, c]"},
+	})
+}
+
+func TestStrip(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`strip("!!hi?!", "!?")`, "hi"},
+		{"strip(\"  hi \n\")", "hi"},
+	})
+}
+
+func TestDeepJoin(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`join([1, [2, [3, 4]]], ",")`, "1,[2, [3, 4]]"},
+		{`join([1, [2, [3, 4]]], ",", true)`, "1,2,3,4"},
+		{`join([[["a"]], "b"], "-", true)`, "a-b"},
+	})
+}
+
+// Containers printed by print and str are truncated, while their elements are kept
+func TestLargeContainers(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`str(range(0, 5))`, "[0, 1, 2, 3, 4]"},
+		{`len(str(range(0, 1000))) < 1000`, "true"},
+		{`len(range(0, 1000))`, "1000"},
+	})
+}
+
+func TestMaxInspectLength(t *testing.T) {
+	defer func(length int) { object.MaxInspectLength = length }(object.MaxInspectLength)
+	object.MaxInspectLength = 10
+	runEvalTests(t, []struct{ input, expected string }{
+		{`str(range(0, 100))`, "[0, 1, 2, ..."},
+		{`len(range(0, 100))`, "100"},
+	})
+}
+
+func TestDeepEqual(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`deepEqual([1, {"a": [2]}], [1, {"a": [2]}])`, "true"},
+		{`deepEqual([1, {"a": [2]}], [1, {"a": [3]}])`, "false"},
+		{`[deepEqual(1, 1.0), deepEqual("1", 1), deepEqual([], {})]`, "[true, false, false]"},
+	})
+}
+
+func TestThaw(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`thaw([1, [2, 3], {"a": (4, [5])}])`, `[1, [2, 3], {"a": (4, [5])}]`},
+		{`let original = [1, [2]]; let copy = thaw(original); copy[0:1] = [9]; [original, copy]`, `[[1, [2]], [9, [2]]]`},
+		{`let original = {"a": [1]}; deepEqual(thaw(original), original)`, "true"},
+		{`let original = [1]; thaw(original) == original`, "false"},
+		{`thaw(5)`, "5"},
+		{`thaw()`, "EVAL ERROR: Wrong number of arguments. Got=0 want=1"},
+	})
+}
+
+// Changing any level of the copy must leave the original as it was
+func TestThawCopiesEveryLevel(t *testing.T) {
+	shared := &object.Array{Elements: []object.Object{&object.Integer{Value: 1}}}
+	original := testEval(t, `[1, [2, [3]], {"a": [4]}, (5, [6])]`).(*object.Array)
+	original.Elements = append(original.Elements, shared, shared)
+	before := original.Inspect()
+
+	copied := thaw(original).(*object.Array)
+	var clear func(obj object.Object)
+	clear = func(obj object.Object) {
+		switch obj := obj.(type) {
+		case *object.Array:
+			for idx := range obj.Elements {
+				clear(obj.Elements[idx])
+				obj.Elements[idx] = NULL
+			}
+		case *object.Tuple:
+			for idx := range obj.Elements {
+				clear(obj.Elements[idx])
+				obj.Elements[idx] = NULL
+			}
+		case *object.Hash:
+			for key, pair := range obj.Pairs {
+				clear(pair.Value)
+				obj.Pairs[key] = object.HashPair{Key: pair.Key, Value: NULL}
+			}
+		}
+	}
+	if copied.Elements[4] != copied.Elements[5] {
+		t.Errorf("Expected the shared array to stay shared in the copy")
+	}
+	clear(copied)
+	if after := original.Inspect(); after != before {
+		t.Errorf("Expected original %s to be unchanged, got %s", before, after)
+	}
+}
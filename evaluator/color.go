@@ -0,0 +1,58 @@
+package evaluator
+
+import (
+	"os"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Whether color builtin wraps strings in ANSI color codes
+// Disabled if NO_COLOR environment variable is set (https://no-color.org)
+var UseColor = os.Getenv("NO_COLOR") == ""
+
+const colorReset = "\033[0m"
+
+// ANSI codes of the colors supported by color builtin
+var colorCodes = map[string]string{
+	"black":   "\033[30m",
+	"red":     "\033[31m",
+	"green":   "\033[32m",
+	"yellow":  "\033[33m",
+	"blue":    "\033[34m",
+	"magenta": "\033[35m",
+	"cyan":    "\033[36m",
+	"white":   "\033[37m",
+	"bold":    "\033[1m",
+}
+
+// Returns the string wrapped in ANSI codes of the color
+// If coloring is disabled, then returns the string as it is
+func color(arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ || arguments[1].Type() != object.STRING_OBJ {
+		return newError("Arguments to color must be STRING. Got %s, %s", arguments[0].Type(), arguments[1].Type())
+	}
+	name := arguments[1].(*object.String).Value
+	code, ok := colorCodes[name]
+	if !ok {
+		return newError("Unknown color: %s", name)
+	}
+	if !UseColor {
+		return arguments[0]
+	}
+	return &object.String{Value: code + arguments[0].(*object.String).Value + colorReset}
+}
+
+// Enables or disables coloring by color builtin
+func useColor(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	if arguments[0].Type() != object.BOOLEAN_OBJ {
+		return newError("Argument to useColor must be BOOLEAN. Got %s", arguments[0].Type())
+	}
+	UseColor = arguments[0].(*object.Boolean).Value
+	return nil
+}
@@ -0,0 +1,124 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Calls the function with rest of the arguments on a separate goroutine
+// Returns a task, which can be waited for the result of the function
+func spawn(arguments ...object.Object) object.Object {
+	if len(arguments) < 1 {
+		return newError("Wrong number of arguments. Got=%d want=minimum 1", len(arguments))
+	}
+	function := arguments[0]
+	if function.Type() != object.FUNCTION_OBJ && function.Type() != object.BUILTIN_OBJ {
+		return newError("First argument to spawn must be FUNCTION/BUILTIN. Got %s", function.Type())
+	}
+	task := &object.Task{Done: make(chan struct{})}
+	go func() {
+		defer close(task.Done)
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				task.Result = newError("Spawned task failed: %s", fmt.Sprint(recovered))
+			}
+		}()
+		task.Result = applyFunction(function, arguments[1:])
+	}()
+	return task
+}
+
+// Blocks until the spawned task is completed and returns its result
+func wait(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	task, ok := arguments[0].(*object.Task)
+	if !ok {
+		return newError("Argument to wait must be TASK. Got %s", arguments[0].Type())
+	}
+	<-task.Done
+	if task.Result == nil {
+		return NULL
+	}
+	return task.Result
+}
+
+// Returns a new channel
+// Channel is unbuffered, if buffer size is not supplied
+func makeChannel(arguments ...object.Object) object.Object {
+	if len(arguments) > 1 {
+		return newError("Wrong number of arguments. Got=%d want=(min:0, max: 1)", len(arguments))
+	}
+	size := 0
+	if len(arguments) == 1 {
+		if arguments[0].Type() != object.INTEGER_OBJ {
+			return newError("Buffer size to chan must be INTEGER. Got %s", arguments[0].Type())
+		}
+		size = arguments[0].(*object.Integer).Value
+		if size < 0 {
+			return newError("Buffer size to chan must be non-negative. Got %d", size)
+		}
+	}
+	return &object.Channel{Value: make(chan object.Object, size)}
+}
+
+// Sends a value to the channel
+// Blocks until the value is received, or buffered
+func send(arguments ...object.Object) (result object.Object) {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	channel, ok := arguments[0].(*object.Channel)
+	if !ok {
+		return newError("First argument to send must be CHANNEL. Got %s", arguments[0].Type())
+	}
+	defer func() {
+		if recover() != nil {
+			result = newError("Cannot send to a closed channel")
+		}
+	}()
+	channel.Value <- arguments[1]
+	return nil
+}
+
+// Receives a value from the channel
+// Blocks until a value is available. Returns NULL if channel is closed
+func recv(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	channel, ok := arguments[0].(*object.Channel)
+	if !ok {
+		return newError("Argument to recv must be CHANNEL. Got %s", arguments[0].Type())
+	}
+	value, ok := <-channel.Value
+	if !ok {
+		return NULL
+	}
+	return value
+}
+
+// Closes the channel, so that no more values can be sent to it
+// Generators can be closed as well, which stops them from producing more values
+func closeChannel(arguments ...object.Object) (result object.Object) {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	if generator, ok := arguments[0].(*object.Generator); ok {
+		generator.Close()
+		return nil
+	}
+	channel, ok := arguments[0].(*object.Channel)
+	if !ok {
+		return newError("Argument to close must be CHANNEL or GENERATOR. Got %s", arguments[0].Type())
+	}
+	defer func() {
+		if recover() != nil {
+			result = newError("Channel is already closed")
+		}
+	}()
+	close(channel.Value)
+	return nil
+}
@@ -0,0 +1,28 @@
+package evaluator
+
+import "testing"
+
+func TestChannels(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let c = chan(2); send(c, 1); send(c, 2); [recv(c), recv(c)]`, "[1, 2]"},
+		{`let c = chan(1); close(c); recv(c)`, "null"},
+		{`let task = spawn(fn(a, b) { a + b }, 1, 2); wait(task)`, "3"},
+		{`let task = spawn(fn() { 1 / 0 }); wait(task)`, "EVAL ERROR: Division by 0 is not allowed"},
+	})
+}
+
+// Producer sends values over a channel, which the consumer adds until the channel is closed
+func TestProducerConsumer(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let c = chan()
+		let producer = spawn(fn() {
+			for i in range(1, 101) { send(c, i) }
+			close(c)
+		})
+		let total = 0
+		let value = recv(c)
+		while type(value) != "NULL" { total = total + value; value = recv(c) }
+		wait(producer)
+		total`, "5050"},
+	})
+}
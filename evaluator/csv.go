@@ -0,0 +1,121 @@
+package evaluator
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Builtin csv module
+// Example: import "csv"; csv.parse("name,age\nfro,1", {"header": true})
+var csvModule = &object.Module{
+	Name: "csv",
+	Members: map[string]object.Object{
+		"parse":     &object.Builtin{Fn: csvParse},
+		"stringify": &object.Builtin{Fn: csvStringify},
+	},
+}
+
+// Returns an array of rows from a CSV string, where each row is an array of strings
+// If header option is true, then first row is used as keys and each of the remaining rows will be a hash
+func csvParse(arguments ...object.Object) object.Object {
+	if 1 > len(arguments) || len(arguments) > 2 {
+		return newError("Wrong number of arguments. Got=%d want=(min:1, max: 2)", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ {
+		return newError("First argument to parse must be STRING. Got %s", arguments[0].Type())
+	}
+	header := false
+	if len(arguments) == 2 {
+		options, ok := arguments[1].(*object.Hash)
+		if !ok {
+			return newError("Options to parse must be HASH. Got %s", arguments[1].Type())
+		}
+		for _, pair := range options.Pairs {
+			if pair.Key.Type() != object.STRING_OBJ || pair.Key.Inspect() != "header" {
+				return newError("Unknown option to parse: %s", pair.Key.Inspect())
+			}
+			header = isTrue(pair.Value)
+		}
+	}
+
+	reader := csv.NewReader(strings.NewReader(arguments[0].(*object.String).Value))
+	records, err := reader.ReadAll()
+	if err != nil {
+		return newError("Invalid CSV: %s", err)
+	}
+	rows := []object.Object{}
+	if header && len(records) > 0 {
+		keys := records[0]
+		for _, record := range records[1:] {
+			values := make(map[string]object.Object, len(keys))
+			for idx, key := range keys {
+				values[key] = &object.String{Value: record[idx]}
+			}
+			rows = append(rows, newStringKeyHash(values))
+		}
+		return &object.Array{Elements: rows}
+	}
+	for _, record := range records {
+		fields := make([]object.Object, len(record), len(record))
+		for idx, field := range record {
+			fields[idx] = &object.String{Value: field}
+		}
+		rows = append(rows, &object.Array{Elements: fields})
+	}
+	return &object.Array{Elements: rows}
+}
+
+// Returns a CSV string created from an array of rows, where each row is an array
+// Fields containing comma, quote or new line are quoted
+// Each field must be a string, number or boolean. Strings are written as they are
+func csvStringify(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	rows, ok := arguments[0].(*object.Array)
+	if !ok {
+		return newError("Argument to stringify must be ARRAY. Got %s", arguments[0].Type())
+	}
+	var str strings.Builder
+	writer := csv.NewWriter(&str)
+	for _, row := range rows.Elements {
+		fields, ok := row.(*object.Array)
+		if !ok {
+			return newError("Each row to stringify must be ARRAY. Got %s", row.Type())
+		}
+		record := make([]string, len(fields.Elements), len(fields.Elements))
+		for idx, field := range fields.Elements {
+			value, err := csvField(field)
+			if err != nil {
+				return err
+			}
+			record[idx] = value
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return newError("Cannot stringify to CSV: %s", err)
+	}
+	return &object.String{Value: str.String()}
+}
+
+// Returns the text of a CSV field
+// Numbers are written with full precision. Return error for values that are not scalar (Eg: arrays, null)
+func csvField(field object.Object) (string, *object.Error) {
+	switch field := field.(type) {
+	case *object.String:
+		return field.Value, nil
+	case *object.Integer:
+		return strconv.Itoa(field.Value), nil
+	case *object.Float:
+		return strconv.FormatFloat(field.Value, 'g', -1, 64), nil
+	case *object.Boolean:
+		return strconv.FormatBool(field.Value), nil
+	default:
+		return "", newError("Field of type %s cannot be written to CSV", field.Type())
+	}
+}
@@ -0,0 +1,37 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/mochatek/frolang/object"
+)
+
+func TestCsvParse(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{"import \"csv\"; csv.parse(\"a,b\n1,2\")", "[[a, b], [1, 2]]"},
+		{"import \"csv\"; let rows = csv.parse(\"name,age\nfro,1\", {\"header\": true}); [rows[0][\"name\"], rows[0][\"age\"]]", "[fro, 1]"},
+	})
+}
+
+// Quoted fields can contain separators, new lines and doubled quotes
+// Strings of the language cannot contain quotes, so the text is passed directly
+func TestCsvParseQuotedFields(t *testing.T) {
+	text := &object.String{Value: "\"x, y\",\"line\nbreak\",\"say \"\"hi\"\"\"\n"}
+	if result := csvParse(text).Inspect(); result != "[[x, y, line\nbreak, say \"hi\"]]" {
+		t.Errorf("Expected quoted fields to be unquoted, got %q", result)
+	}
+}
+
+func TestCsvStringify(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`import "csv"; csv.stringify([["name", "age"], ["fro", 1]])`, "name,age\nfro,1\n"},
+		{`import "csv"; csv.stringify([[0.1, 1.5, 100000000.0, -2.0]])`, "0.1,1.5,1e+08,-2\n"},
+		{`import "csv"; csv.stringify([[true, "a,b"]])`, "true,\"a,b\"\n"},
+		{`import "csv"; csv.stringify([["1.50", "x"]])`, "1.50,x\n"},
+		{`import "csv"; csv.stringify([[[1, 2]]])`, "EVAL ERROR: Field of type ARRAY cannot be written to CSV"},
+		{`import "csv"; csv.stringify([[{"a": 1}]])`, "EVAL ERROR: Field of type HASH cannot be written to CSV"},
+		{`import "csv"; csv.stringify([[print()]])`, "EVAL ERROR: Field of type NULL cannot be written to CSV"},
+		{`import "csv"; csv.stringify([1])`, "EVAL ERROR: Each row to stringify must be ARRAY. Got INTEGER"},
+		{`import "csv"; let rows = [["x", 1.25]]; csv.parse(csv.stringify(rows))[0][1] == "1.25"`, "true"},
+	})
+}
@@ -0,0 +1,33 @@
+package evaluator
+
+import (
+	"github.com/mochatek/frolang/ast"
+	"github.com/mochatek/frolang/object"
+)
+
+// Hooks called by the evaluator around each statement, so that hosts can implement breakpoints, watches etc
+// Before is called with the environment in which the statement is evaluated, and After with its result
+// Block statements are not reported, but the statements inside them are
+// Statements of spawned tasks are reported from their goroutines, so the hooks should be safe for concurrent use
+type Debugger interface {
+	Before(statement ast.Statement, env *object.Environment)
+	After(statement ast.Statement, result object.Object)
+}
+
+// Debugger which is also notified of the calls of user functions
+// Call is called before evaluating the body of the function, and the returned function after it
+type CallDebugger interface {
+	Debugger
+	Call(function *object.Function) (done func())
+}
+
+// Evaluates the statement between the calls to the hooks of the debugger
+func debugStatement(statement ast.Statement, env *object.Environment, debugger Debugger) object.Object {
+	if _, ok := statement.(*ast.BlockStatement); ok {
+		return evalNode(statement, env)
+	}
+	debugger.Before(statement, env)
+	result := evalNode(statement, env)
+	debugger.After(statement, result)
+	return result
+}
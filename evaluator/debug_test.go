@@ -0,0 +1,68 @@
+package evaluator
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/mochatek/frolang/ast"
+	"github.com/mochatek/frolang/lexer"
+	"github.com/mochatek/frolang/object"
+	"github.com/mochatek/frolang/parser"
+)
+
+// Debugger counting the statements which were evaluated, and the statements whose result was an error
+type countingDebugger struct {
+	mutex  sync.Mutex
+	before int
+	after  int
+	errors int
+}
+
+func (debugger *countingDebugger) Before(statement ast.Statement, env *object.Environment) {
+	debugger.mutex.Lock()
+	defer debugger.mutex.Unlock()
+	debugger.before++
+}
+
+func (debugger *countingDebugger) After(statement ast.Statement, result object.Object) {
+	debugger.mutex.Lock()
+	defer debugger.mutex.Unlock()
+	debugger.after++
+	if isError(result) {
+		debugger.errors++
+	}
+}
+
+// Each run is notified to its own debugger, even when the runs are evaluated at the same time
+func TestDebugger(t *testing.T) {
+	tests := []struct {
+		input      string
+		statements int
+		errors     int
+	}{
+		{`let x = 1; x`, 2, 0},
+		{"let n = 0\nfor i in [1, 2, 3] { n = n + i }", 5, 0},
+		{"let f = fn() { let a = 1; a }\nf(); f()", 7, 0},
+		{`let x = 1 / 0`, 1, 1},
+	}
+	debuggers := make([]*countingDebugger, len(tests))
+	var wait sync.WaitGroup
+	for index, test := range tests {
+		debuggers[index] = &countingDebugger{}
+		wait.Add(1)
+		go func(input string, debugger *countingDebugger) {
+			defer wait.Done()
+			env := object.NewEnvironment()
+			env.SetOptions(&Options{Debugger: debugger})
+			Eval(parser.New(lexer.New(input)).ParseProgram(), env)
+		}(test.input, debuggers[index])
+	}
+	wait.Wait()
+	for index, test := range tests {
+		debugger := debuggers[index]
+		if debugger.before != test.statements || debugger.after != test.statements || debugger.errors != test.errors {
+			t.Errorf("%q: expected %d statements with %d errors, got before=%d after=%d errors=%d",
+				test.input, test.statements, test.errors, debugger.before, debugger.after, debugger.errors)
+		}
+	}
+}
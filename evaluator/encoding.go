@@ -0,0 +1,61 @@
+package evaluator
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Builtin encode module
+// Example: import "encode"; encode.base64("FroLang")
+var encodeModule = &object.Module{
+	Name: "encode",
+	Members: map[string]object.Object{
+		"base64": encoder("base64", base64.StdEncoding.EncodeToString),
+		"hex":    encoder("hex", hex.EncodeToString),
+	},
+}
+
+// Builtin decode module
+// Example: import "decode"; decode.base64("RnJvTGFuZw==")
+var decodeModule = &object.Module{
+	Name: "decode",
+	Members: map[string]object.Object{
+		"base64": decoder("base64", base64.StdEncoding.DecodeString),
+		"hex":    decoder("hex", hex.DecodeString),
+	},
+}
+
+// Creates a builtin that encodes the bytes of a string
+func encoder(name string, encode func([]byte) string) *object.Builtin {
+	return &object.Builtin{Fn: func(arguments ...object.Object) object.Object {
+		if len(arguments) != 1 {
+			return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+		}
+		str, ok := arguments[0].(*object.String)
+		if !ok {
+			return newError("Argument to encode.%s must be STRING. Got %s", name, arguments[0].Type())
+		}
+		return &object.String{Value: encode([]byte(str.Value))}
+	}}
+}
+
+// Creates a builtin that decodes a string into the string of decoded bytes
+// Return error if the string is not validly encoded
+func decoder(name string, decode func(string) ([]byte, error)) *object.Builtin {
+	return &object.Builtin{Fn: func(arguments ...object.Object) object.Object {
+		if len(arguments) != 1 {
+			return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+		}
+		str, ok := arguments[0].(*object.String)
+		if !ok {
+			return newError("Argument to decode.%s must be STRING. Got %s", name, arguments[0].Type())
+		}
+		decoded, err := decode(str.Value)
+		if err != nil {
+			return newError("Invalid %s: %s", name, err)
+		}
+		return &object.String{Value: string(decoded)}
+	}}
+}
@@ -2,6 +2,7 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/mochatek/frolang/ast"
 	"github.com/mochatek/frolang/object"
@@ -20,6 +21,9 @@ func newError(format string, rest ...interface{}) *object.Error {
 	return &object.Error{Message: fmt.Sprintf(format, rest...)}
 }
 
+// Maximum number of calls recorded in the stack of an error, so that deep recursion doesn't make it huge
+const maxStackFrames = 20
+
 // Function to check whether the supplied object is an error or not
 func isError(obj object.Object) bool {
 	if obj != nil {
@@ -28,9 +32,50 @@ func isError(obj object.Object) bool {
 	return false
 }
 
+// Function to check whether the supplied object interrupts the evaluation of remaining statements
+// Errors, return values and jumps (break/continue) are interrupts
+func isInterrupt(obj object.Object) bool {
+	if obj != nil {
+		objectType := obj.Type()
+		return objectType == object.ERROR_OBJ || objectType == object.RETURN_OBJ || objectType == object.JUMP_OBJ
+	}
+	return false
+}
+
+// Evaluate the AST like Eval, but recover from any panic raised while evaluating
+// The panic is converted into an error object, so that the caller (REPL) can keep running
+func SafeEval(node ast.Node, env *object.Environment) (result object.Object) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result = newError("Runtime panic: %s", fmt.Sprint(recovered))
+		}
+	}()
+	return Eval(node, env)
+}
+
 // Function to evaluate AST to object
 // Based on the node's type, call the appropriate evaluator and return the resultant object
+// A missing node (from a failed parse) is reported as an error instead of being dereferenced
+// If the options of the run has a trace writer, the node is logged to it before it is evaluated
+// If the options of the run has a debugger, it is notified before and after evaluating each statement
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	if node == nil {
+		return newError("Cannot evaluate an incomplete expression")
+	}
+	options := optionsOf(env)
+	if options.Trace != nil {
+		traceNode(options.Trace, node)
+	}
+	if options.Debugger != nil {
+		if statement, ok := node.(ast.Statement); ok {
+			return debugStatement(statement, env, options.Debugger)
+		}
+	}
+	return evalNode(node, env)
+}
+
+// Evaluates the node based on its type
+func evalNode(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
@@ -38,6 +83,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalLetStatement(node, env)
 	case *ast.ReturnStatement:
 		return evalReturnStatement(node, env)
+	case *ast.YieldStatement:
+		return evalYieldStatement(node, env)
 	case *ast.BlockStatement:
 		return evalBlockStatement(node, env)
 	case *ast.ForStatement:
@@ -50,18 +97,36 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return &object.Jump{Signal: node.TokenLiteral()}
 	case *ast.TryStatement:
 		return evalTryStatement(node, env)
+	case *ast.WithStatement:
+		return evalWithStatement(node, env)
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
+	case *ast.ExportStatement:
+		return newError("export statement can only be used at top level at %s", node.Token.Location)
 	case *ast.ExpressionStatement:
 		return Eval(node.Expression, env)
 	case *ast.PrefixExpression:
 		return evalPrefixExpression(node, env)
 	case *ast.InfixExpression:
 		return evalInfixExpression(node, env)
+	case *ast.PostfixExpression:
+		return evalPostfixExpression(node, env)
+	case *ast.ComparisonExpression:
+		return evalComparisonExpression(node, env)
 	case *ast.AssignExpression:
 		return evalAssignExpression(node, env)
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
 	case *ast.IndexExpression:
 		return evalIndexExpression(node, env)
+	case *ast.MemberExpression:
+		return evalMemberExpression(node, env)
+	case *ast.SliceExpression:
+		return evalSliceExpression(node, env)
+	case *ast.SpreadExpression:
+		return newError("Spread can only be used in array literal or function call at %s", node.Token.Location)
 	case *ast.CallExpression:
 		return evalCallExpression(node, env)
 	case *ast.Identifier:
@@ -76,10 +141,14 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return &object.String{Value: node.Value}
 	case *ast.ArrayLiteral:
 		return evalArrayLiteral(node, env)
+	case *ast.TupleLiteral:
+		return evalTupleLiteral(node, env)
 	case *ast.HashLiteral:
 		return evalHashLiteral(node, env)
+	case *ast.ComprehensionExpression:
+		return evalComprehensionExpression(node, env)
 	case *ast.FunctionLiteral:
-		return &object.Function{Parameters: node.Parameters, Body: node.Body, Env: env}
+		return &object.Function{Name: node.Name, Generator: node.Generator, Parameters: node.Parameters, Body: node.Body, Env: env}
 	}
 	return nil
 }
@@ -89,37 +158,51 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 // Similarly if we encounter an error object, return the result there itself
 // In both cases no further statements will be evaluated
 // In case of jump object, reason will be use of break/continue outside loop. So return that error
+// Export statements are only evaluated here, as they are allowed only at the top level
+// Deferred expressions are evaluated before returning the result
 func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	var result object.Object
+	deferred := []*ast.DeferStatement{}
 	for _, statement := range program.Statements {
-		result = Eval(statement, env)
-		switch result := result.(type) {
-		case *object.ReturnValue:
-			return result.Value
-		case *object.Error:
-			return result
-		case *object.Jump:
-			return newError("%s statement can only be used inside loop", result.Signal)
+		switch statement := statement.(type) {
+		case *ast.ExportStatement:
+			result = evalLetStatement(statement.Statement, env)
+		case *ast.DeferStatement:
+			deferred = append(deferred, statement)
+			result = nil
+		default:
+			result = Eval(statement, env)
+		}
+		if isInterrupt(result) {
+			break
 		}
 	}
-	return result
+	return unwrapReturnValue(evalDeferred(deferred, env, result))
 }
 
 // Evaluates the value assigned to an identifier.
 // If the evaluation was successful, then set the variable in environment
+// If evaluation did not result in a value, then set NULL
 // If evaluated object was error, then directly return it
 func evalLetStatement(LetStatement *ast.LetStatement, env *object.Environment) object.Object {
 	value := Eval(LetStatement.Value, env)
 	if isError(value) {
 		return value
 	}
+	if value == nil {
+		value = NULL
+	}
 	env.Set(LetStatement.Name.Value, value)
 	return nil
 }
 
 // Evaluates the return value of a return statement
 // If evaluated object was error, then directly return it
+// Return statement without value returns NULL
 func evalReturnStatement(returnStatement *ast.ReturnStatement, env *object.Environment) object.Object {
+	if returnStatement.ReturnValue == nil {
+		return &object.ReturnValue{Value: NULL}
+	}
 	returnValue := Eval(returnStatement.ReturnValue, env)
 	if isError(returnValue) {
 		return returnValue
@@ -130,24 +213,36 @@ func evalReturnStatement(returnStatement *ast.ReturnStatement, env *object.Envir
 // Evaluates a block statement
 // Provision a local environment for the block
 // Evaluate each statement in the block with the local environment
-// Return error immediately if any statement evaluated to error
-// Return the result immediately if we encounter return/jump statement
-// Otherwise return the final result as in parseProgram
+// Stop evaluating if any statement evaluated to error or if we encounter return/jump statement
+// Collect the deferred expressions and evaluate them before the block exits
+// Return the final result as in parseProgram
 func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
 	var result object.Object
+	deferred := []*ast.DeferStatement{}
 	localEnv := object.NewEnclosedEnvironment(env)
 	for _, statement := range block.Statements {
+		if deferStatement, ok := statement.(*ast.DeferStatement); ok {
+			deferred = append(deferred, deferStatement)
+			result = nil
+			continue
+		}
 		result = Eval(statement, localEnv)
-		if isError(result) {
-			return result
+		if isInterrupt(result) {
+			break
 		}
-		if result != nil {
-			if result.Type() == object.RETURN_OBJ {
-				return result
-			}
-			if result.Type() == object.JUMP_OBJ {
-				return result
-			}
+	}
+	return evalDeferred(deferred, localEnv, result)
+}
+
+// Evaluates the deferred expressions in reverse order (LIFO)
+// Every deferred expression is evaluated, even if the block resulted in error
+// If a deferred expression evaluates to error, then it becomes the result, unless block already resulted in error
+// Otherwise the result of the block is returned as such
+func evalDeferred(deferred []*ast.DeferStatement, env *object.Environment, result object.Object) object.Object {
+	for index := len(deferred) - 1; index >= 0; index-- {
+		value := Eval(deferred[index].Expression, env)
+		if isError(value) && !isError(result) {
+			result = value
 		}
 	}
 	return result
@@ -156,23 +251,48 @@ func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) obje
 // Evaluates a for statement
 // If object is not iterable, then return error
 // Else, provision a local environment
-// Get the elements from the iterable object
+// Get the elements from the iterable object one at a time, so that generators are consumed lazily
 // Repeatedly evaluate the body length(element) times
 // Return error immediately if body evaluates to error
 // Return the result immediately if returnValue is evaluated
 // If jump object is evaluated, do the appropriate jump operation in loop
 // Before each iteration, set the element in the local environment
+// If guard was supplied, then skip the elements for which it is falsy
+// Return error if the loop exceeds the iteration limit
+// If the loop created a generator, then it is closed when the loop exits, even if it exits early
 func evalForStatement(forStatement *ast.ForStatement, env *object.Environment) object.Object {
 	iterObject := Eval(forStatement.Iterator, env)
 	iterable, ok := iterObject.(object.Iterable)
 	if !ok {
 		return newError("%s: is not iterable", iterObject.Type())
 	}
+	if generator, ok := loopGenerator(forStatement.Iterator, iterable); ok {
+		defer generator.Close()
+	}
 	elementName := forStatement.Element.Value
 	localEnv := object.NewEnclosedEnvironment(env)
-	array := iterable.Iter().Elements
-	for _, item := range array {
+	nextElement := elementIterator(iterable)
+	for iteration := 0; ; iteration++ {
+		item, ok := nextElement()
+		if !ok {
+			break
+		}
+		if isError(item) {
+			return item
+		}
+		if iterationLimitExceeded(env, iteration) {
+			return newError("Iteration limit exceeded")
+		}
 		localEnv.Set(elementName, item)
+		if forStatement.Guard != nil {
+			guard := Eval(forStatement.Guard, localEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTrue(guard) {
+				continue
+			}
+		}
 		result := Eval(forStatement.Body, localEnv)
 		if isError(result) {
 			return result
@@ -200,14 +320,18 @@ func evalForStatement(forStatement *ast.ForStatement, env *object.Environment) o
 // Return the result immediately if returnValue is evaluated
 // If jump object is evaluated, do the appropriate jump operation in loop
 // If condition returned false, then break from loop
+// Return error if the loop exceeds the iteration limit
 func evalWhileStatement(whileStatement *ast.WhileStatement, env *object.Environment) object.Object {
 	localEnv := object.NewEnclosedEnvironment(env)
-	for {
+	for iteration := 0; ; iteration++ {
 		condition := Eval(whileStatement.Condition, localEnv)
 		if isError(condition) {
 			return condition
 		}
 		if isTrue(condition) {
+			if iterationLimitExceeded(env, iteration) {
+				return newError("Iteration limit exceeded")
+			}
 			result := Eval(whileStatement.Body, localEnv)
 			if isError(result) {
 				return result
@@ -230,6 +354,12 @@ func evalWhileStatement(whileStatement *ast.WhileStatement, env *object.Environm
 	return nil
 }
 
+// Check whether the loop already ran the maximum number of iterations allowed by the options of the run
+func iterationLimitExceeded(env *object.Environment, iteration int) bool {
+	maxIterations := optionsOf(env).MaxIterations
+	return maxIterations > 0 && iteration >= maxIterations
+}
+
 // Provision a local environment
 // Evaluate result of try block
 // If it resulted in an error, then create a string with error message
@@ -268,6 +398,30 @@ func evalTryStatement(tryStatement *ast.TryStatement, env *object.Environment) o
 	return nil
 }
 
+// Evaluate the resource. In case of error, return it
+// Return error if resource cannot be closed
+// Else, provision a local environment with the resource set to the identifier
+// Evaluate the body and close the resource, regardless of the result
+// If closing failed, return that error unless the body already resulted in error
+// Otherwise return the result of body, so that return/jump statements are propagated
+func evalWithStatement(withStatement *ast.WithStatement, env *object.Environment) object.Object {
+	resource := Eval(withStatement.Resource, env)
+	if isError(resource) {
+		return resource
+	}
+	closer, ok := resource.(object.Closer)
+	if !ok {
+		return newError("%s: is not a resource", resource.Type())
+	}
+	localEnv := object.NewEnclosedEnvironment(env)
+	localEnv.Set(withStatement.Name.Value, resource)
+	result := Eval(withStatement.Body, localEnv)
+	if err := closer.Close(); err != nil && !isError(result) {
+		return newError("Cannot close %s: %s", resource.Type(), err)
+	}
+	return result
+}
+
 // Evaluates an prefix expression
 // If right operand was evaluated to error object, then return it directly
 // If the operator is a valid prefix operator, then perform that operation on the right operand and return result
@@ -297,31 +451,261 @@ func evalInfixExpression(infixExpression *ast.InfixExpression, env *object.Envir
 	if isError(leftOperand) {
 		return leftOperand
 	}
+	if leftOperand == nil {
+		leftOperand = NULL
+	}
+	// Right operand of ?? is evaluated only if the left operand is null
+	if infixExpression.Operator == token.COALESCE && leftOperand != NULL {
+		return leftOperand
+	}
 	rightOperand := Eval(infixExpression.Right, env)
 	if isError(rightOperand) {
 		return rightOperand
 	}
+	if rightOperand == nil {
+		rightOperand = NULL
+	}
 	operator := infixExpression.Operator
 	return evalInfixOperation(leftOperand, operator, rightOperand)
 }
 
+// Evaluates a chained comparison like a < b < c as a < b & b < c
+// Each operand is evaluated at most once
+// Evaluation stops at the first comparison which is false or results in error
+func evalComparisonExpression(comparisonExpression *ast.ComparisonExpression, env *object.Environment) object.Object {
+	leftOperand := Eval(comparisonExpression.Operands[0], env)
+	if isError(leftOperand) {
+		return leftOperand
+	}
+	if leftOperand == nil {
+		leftOperand = NULL
+	}
+	for index, operator := range comparisonExpression.Operators {
+		rightOperand := Eval(comparisonExpression.Operands[index+1], env)
+		if isError(rightOperand) {
+			return rightOperand
+		}
+		if rightOperand == nil {
+			rightOperand = NULL
+		}
+		result := evalInfixOperation(leftOperand, operator, rightOperand)
+		if isError(result) || !isTrue(result) {
+			return result
+		}
+		leftOperand = rightOperand
+	}
+	return TRUE
+}
+
 // Evaluated assignment expression
 // Return error if variable is not defined before
 // Else, evaluate the value
 // If value evaluated to error, then return it
+// If a slice of the variable is assigned, then the value is a new array with that range replaced
 // Else, update value of that variable in env and return the value
 func evalAssignExpression(assignExpression *ast.AssignExpression, env *object.Environment) object.Object {
 	variable := assignExpression.Variable
-	if _, ok := env.Get(variable.Value); !ok {
+	current, ok := env.Get(variable.Value)
+	if !ok {
 		return newError("Identifier: %s is not defined at %s", variable.Value, variable.Token.Location)
 	}
 	value := Eval(assignExpression.Value, env)
 	if isError(value) {
 		return value
 	}
+	if assignExpression.Slice != nil {
+		value = evalSliceAssignment(assignExpression.Slice, current, value, env)
+		if isError(value) {
+			return value
+		}
+	}
 	return env.Update(variable.Value, value)
 }
 
+// Evaluates a postfix increment/decrement expression
+// Return error if variable is not defined before or if its value is not a number
+// Else, update the variable with incremented/decremented value and return the value before update
+func evalPostfixExpression(postfixExpression *ast.PostfixExpression, env *object.Environment) object.Object {
+	variable := postfixExpression.Variable
+	current, ok := env.Get(variable.Value)
+	if !ok {
+		return newError("Identifier: %s is not defined at %s", variable.Value, variable.Token.Location)
+	}
+	delta := 1
+	if postfixExpression.Operator == token.DECREMENT {
+		delta = -1
+	}
+	switch value := current.(type) {
+	case *object.Integer:
+		env.Update(variable.Value, &object.Integer{Value: value.Value + delta})
+	case *object.Float:
+		env.Update(variable.Value, &object.Float{Value: value.Value + float64(delta)})
+	default:
+		return newError("Cannot apply %s to %s at %s", postfixExpression.Operator, current.Type(), postfixExpression.Token.Location)
+	}
+	return current
+}
+
+// Replaces the range of the array given by the slice with the elements of the value array
+// Arrays are immutable, so a new array is returned. Its length changes if the lengths of range and value differ
+// Return error if the target is not an array, value is not an array or the range is out of bounds
+func evalSliceAssignment(slice *ast.SliceExpression, target object.Object, value object.Object, env *object.Environment) object.Object {
+	array, ok := target.(*object.Array)
+	if !ok {
+		return newError("Cannot assign to a slice of %s at %s", target.Type(), slice.Token.Location)
+	}
+	replacement, ok := value.(*object.Array)
+	if !ok {
+		return newError("Value assigned to a slice must be ARRAY. Got %s at %s", value.Type(), slice.Token.Location)
+	}
+	start, end, err := evalSliceBounds(slice, len(array.Elements), env)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, 0, len(array.Elements)-(end-start)+len(replacement.Elements))
+	elements = append(elements, array.Elements[:start]...)
+	elements = append(elements, replacement.Elements...)
+	elements = append(elements, array.Elements[end:]...)
+	return &object.Array{Elements: elements}
+}
+
+// Evaluates the start and end of a slice over an iterable of supplied length
+// Omitted start defaults to 0 and omitted end defaults to the length
+// Negative bounds are counted from the end
+// Return error if step is supplied, or if (0 <= start <= end <= length) is not satisfied
+func evalSliceBounds(slice *ast.SliceExpression, length int, env *object.Environment) (int, int, *object.Error) {
+	if slice.Step != nil {
+		return 0, 0, newError("Cannot assign to a slice with step at %s", slice.Token.Location)
+	}
+	bounds := []int{0, length}
+	for index, expression := range []ast.Expression{slice.Start, slice.End} {
+		bound, ok, err := evalSliceBound(slice, expression, env)
+		if err != nil {
+			return 0, 0, err
+		}
+		if !ok {
+			continue
+		}
+		bounds[index] = bound
+		if bounds[index] < 0 {
+			bounds[index] += length
+		}
+	}
+	start, end := bounds[0], bounds[1]
+	if start < 0 || start > end || end > length {
+		return 0, 0, newError("Slice bounds out of range. Got start=%d, end=%d for length %d at %s", start, end, length, slice.Token.Location)
+	}
+	return start, end, nil
+}
+
+// Evaluates a start/end/step of a slice
+// Returns whether the bound was supplied, as omitted bounds take default values
+// Return error if bound is not an integer
+func evalSliceBound(slice *ast.SliceExpression, expression ast.Expression, env *object.Environment) (int, bool, *object.Error) {
+	if expression == nil {
+		return 0, false, nil
+	}
+	bound := Eval(expression, env)
+	if isError(bound) {
+		return 0, false, bound.(*object.Error)
+	}
+	integer, ok := bound.(*object.Integer)
+	if !ok {
+		return 0, false, newError("Slice bounds must be INTEGER. Got %s at %s", bound.Type(), slice.Token.Location)
+	}
+	return integer.Value, true, nil
+}
+
+// Evaluates a slice expression over an array or string
+// Returns a new array/string with the elements from start to end (exclusive), taking every step-th element
+// Negative start and end are counted from the end, and bounds beyond the ends are clamped to the ends
+// Negative step slices from end towards the start
+// Omitted bounds default to the ends, and omitted step defaults to 1
+func evalSliceExpression(slice *ast.SliceExpression, env *object.Environment) object.Object {
+	left := Eval(slice.Left, env)
+	if left == nil {
+		left = NULL
+	}
+	if isError(left) || (slice.Optional && left == NULL) {
+		return left
+	}
+	var length int
+	switch left := left.(type) {
+	case *object.Array:
+		length = len(left.Elements)
+	case *object.String:
+		length = len([]rune(left.Value))
+	default:
+		return newError("Cannot perform slice on %s at %s", left.Type(), slice.Token.Location)
+	}
+	indices, err := evalSliceIndices(slice, length, env)
+	if err != nil {
+		return err
+	}
+	switch left := left.(type) {
+	case *object.Array:
+		elements := make([]object.Object, len(indices))
+		for index, elementIndex := range indices {
+			elements[index] = left.Elements[elementIndex]
+		}
+		return &object.Array{Elements: elements}
+	default:
+		characters := []rune(left.(*object.String).Value)
+		sliced := make([]rune, len(indices))
+		for index, characterIndex := range indices {
+			sliced[index] = characters[characterIndex]
+		}
+		return &object.String{Value: string(sliced)}
+	}
+}
+
+// Returns the indices selected by the slice over an iterable of supplied length
+// Return error if any of the bounds is not an integer or step is 0
+func evalSliceIndices(slice *ast.SliceExpression, length int, env *object.Environment) ([]int, *object.Error) {
+	step, ok, err := evalSliceBound(slice, slice.Step, env)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		step = 1
+	}
+	if step == 0 {
+		return nil, newError("Slice step cannot be 0 at %s", slice.Token.Location)
+	}
+	// Bounds are clamped to [lower, upper]. For negative step, slicing ends just before the first element
+	lower, upper := 0, length
+	if step < 0 {
+		lower, upper = -1, length-1
+	}
+	bounds := []int{lower, upper}
+	if step < 0 {
+		bounds = []int{upper, lower}
+	}
+	for index, expression := range []ast.Expression{slice.Start, slice.End} {
+		bound, ok, err := evalSliceBound(slice, expression, env)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if bound < 0 {
+			bound += length
+		}
+		if bound < lower {
+			bound = lower
+		} else if bound > upper {
+			bound = upper
+		}
+		bounds[index] = bound
+	}
+	indices := []int{}
+	for index := bounds[0]; (step > 0 && index < bounds[1]) || (step < 0 && index > bounds[1]); index += step {
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
 // Evaluates a if expression
 // First evaluated the condition
 // If evaluated object was error, then directly return it
@@ -347,7 +731,10 @@ func evalIfExpression(ifExpression *ast.IfExpression, env *object.Environment) o
 // Return error if operand is not compatible for index operation
 func evalIndexExpression(node *ast.IndexExpression, env *object.Environment) object.Object {
 	left := Eval(node.Array, env)
-	if isError(left) {
+	if left == nil {
+		left = NULL
+	}
+	if isError(left) || (node.Optional && left == NULL) {
 		return left
 	}
 	index := Eval(node.Index, env)
@@ -358,6 +745,8 @@ func evalIndexExpression(node *ast.IndexExpression, env *object.Environment) obj
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
 		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.TUPLE_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalArrayIndexExpression(&object.Array{Elements: left.(*object.Tuple).Elements}, index)
 	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
 		return evalStringIndexExpression(left, index)
 	case left.Type() == object.HASH_OBJ:
@@ -367,6 +756,49 @@ func evalIndexExpression(node *ast.IndexExpression, env *object.Environment) obj
 	}
 }
 
+// Evaluate the object whose member is accessed. In case of error, return it
+// If it is a module, then return the member. Return error if module doesn't have that member
+// If it is a hash, then return the value for property name as string key. Else, return NULL
+// If it is a file, then return the method bound to that file. Return error if there is no such method
+// If it is an array or string, then return the builtin bound to it. Return error if there is no such method
+// Otherwise return error as member access is not supported
+func evalMemberExpression(memberExpression *ast.MemberExpression, env *object.Environment) object.Object {
+	obj := Eval(memberExpression.Object, env)
+	if obj == nil {
+		obj = NULL
+	}
+	if isError(obj) || (memberExpression.Optional && obj == NULL) {
+		return obj
+	}
+	name := memberExpression.Property.Value
+	switch obj := obj.(type) {
+	case *object.Module:
+		if member, ok := obj.Members[name]; ok {
+			return member
+		}
+		return newError("Module: %s has no member %s at %s", obj.Name, name, memberExpression.Property.Token.Location)
+	case *object.Hash:
+		return evalHashIndexExpression(obj, &object.String{Value: name})
+	case *object.File:
+		if method, ok := fileMethod(obj, name); ok {
+			return method
+		}
+		return newError("File has no method %s at %s", name, memberExpression.Property.Token.Location)
+	case *object.Array:
+		if method, ok := arrayMethod(obj, name); ok {
+			return method
+		}
+		return newError("Array has no method %s at %s", name, memberExpression.Property.Token.Location)
+	case *object.String:
+		if method, ok := stringMethod(obj, name); ok {
+			return method
+		}
+		return newError("String has no method %s at %s", name, memberExpression.Property.Token.Location)
+	default:
+		return newError("Member access not supported for: %s.%s", obj.Type(), name)
+	}
+}
+
 // Return index-th element from the array
 // If index exceeded array length, then return NULL
 func evalArrayIndexExpression(array, index object.Object) object.Object {
@@ -398,11 +830,11 @@ func evalStringIndexExpression(str, index object.Object) object.Object {
 // If value was got, then return it. Else, return NULL
 func evalHashIndexExpression(hash, index object.Object) object.Object {
 	hashObject := hash.(*object.Hash)
-	key, ok := index.(object.Hashable)
+	key, ok := object.HashKeyOf(index)
 	if !ok {
 		return newError("Key: %s cannot be hashed", index.Type())
 	}
-	pair, ok := hashObject.Pairs[key.HashKey()]
+	pair, ok := hashObject.Pairs[key]
 	if !ok {
 		return NULL
 	}
@@ -424,41 +856,93 @@ func evalCallExpression(functionCall *ast.CallExpression, env *object.Environmen
 		return arguments[0]
 	}
 
-	return applyFunction(function, arguments)
+	result := applyFunction(function, arguments)
+	if err, ok := result.(*object.Error); ok {
+		if function, ok := function.(*object.Function); ok && len(err.Stack) < maxStackFrames {
+			err.Stack = append(err.Stack, fmt.Sprintf("%s called at %s", calleeName(functionCall, function), functionCall.Token.Location))
+		}
+	}
+	return result
+}
+
+// Name of the called function shown in the stack of errors
+// It is the expression which was called if it is a name like add or math.add, otherwise the name of the function
+func calleeName(functionCall *ast.CallExpression, function *object.Function) string {
+	switch callee := functionCall.Function.(type) {
+	case *ast.Identifier, *ast.MemberExpression:
+		return callee.String()
+	}
+	if function.Name != "" {
+		return function.Name
+	}
+	return "anonymous function"
 }
 
 // Evaluates an array of expressions
+// Elements of the array evaluated from a spread expression are added in its place
+// Expressions which did not result in a value (like call to print) are evaluated to NULL
 // Returns array of evaluated objects as result
 // In case of error, returns a single element array with the error object
 func evalExpressions(expressions []ast.Expression, env *object.Environment) []object.Object {
 	var result []object.Object
 	for _, expression := range expressions {
+		if spreadExpression, ok := expression.(*ast.SpreadExpression); ok {
+			evaluated := Eval(spreadExpression.Value, env)
+			if isError(evaluated) {
+				return []object.Object{evaluated}
+			}
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				return []object.Object{newError("%s: cannot be spread at %s", evaluated.Type(), spreadExpression.Token.Location)}
+			}
+			result = append(result, array.Elements...)
+			continue
+		}
 		evaluated := Eval(expression, env)
 		if isError(evaluated) {
 			return []object.Object{evaluated}
 		}
+		if evaluated == nil {
+			evaluated = NULL
+		}
 		result = append(result, evaluated)
 	}
 	return result
 }
 
-// If function is user defined
-// Then get the local environment for it with all of its argument values set to the parameter identifiers
-// Evaluate that function body on this local environment
+// If function is user defined and the number of arguments differs from its parameters, then return error
+// Else get the local environment for it with all of its argument values set to the parameter identifiers
+// If it is a generator function, then return a generator which evaluates the body on this local environment lazily
+// Else, evaluate that function body on this local environment. Debugger tracking calls is notified around it
 // Determine the return value and return the result (explicit/implicit return)
 // If it was builtin function then call it with the arguments and return the result
 // Otherwise return error
+// Result is NULL if the function gave no value, like print or a body ending in let
 func applyFunction(function object.Object, arguments []object.Object) object.Object {
+	var result object.Object
 	switch function := function.(type) {
 	case *object.Function:
+		if len(arguments) != len(function.Parameters) {
+			return newError("Wrong number of arguments. Got=%d want=%d", len(arguments), len(function.Parameters))
+		}
 		enclosedEnv := getEnclosedFunctionEnv(function, arguments)
+		if function.Generator {
+			return newGenerator(function, enclosedEnv)
+		}
+		if caller, ok := optionsOf(function.Env).Debugger.(CallDebugger); ok {
+			defer caller.Call(function)()
+		}
 		evaluated := Eval(function.Body, enclosedEnv)
-		return unwrapReturnValue(evaluated)
+		result = unwrapReturnValue(evaluated)
 	case *object.Builtin:
-		return function.Fn(arguments...)
+		result = function.Fn(arguments...)
 	default:
 		return newError("%s: not a function", function.Type())
 	}
+	if result == nil {
+		result = NULL
+	}
+	return result
 }
 
 // Creates a local environment for function execution
@@ -475,17 +959,30 @@ func getEnclosedFunctionEnv(function *object.Function, arguments []object.Object
 
 // If the value returned was return value object, It means there was an explicit return statement in body
 // In that case, return the value of that return object
+// If it was a jump object, then break/continue was used outside loop. So return error
 // Otherwise, return the result itself
 func unwrapReturnValue(obj object.Object) object.Object {
-	if returnValue, ok := obj.(*object.ReturnValue); ok {
-		return returnValue.Value
+	switch obj := obj.(type) {
+	case *object.ReturnValue:
+		return obj.Value
+	case *object.Jump:
+		return jumpOutsideLoopError(obj)
 	}
 	return obj
 }
 
+// Returns the error for a break/continue which escaped all the loops
+func jumpOutsideLoopError(jump *object.Jump) *object.Error {
+	return newError("'%s' used outside of a loop", jump.Signal)
+}
+
+// If left operand is a hash overloading the operator, then return the result of its operator method
 // If the operator is a valid infix operator, then perform that operation on the operands and return result
 // Otherwise return unknown operator error
 func evalInfixOperation(leftOperand object.Object, operator string, rightOperand object.Object) object.Object {
+	if result, ok := evalOverloadedOperation(leftOperand, operator, rightOperand); ok {
+		return result
+	}
 	switch {
 	case operator == token.AND:
 		return evalAndExpression(leftOperand, rightOperand)
@@ -493,14 +990,18 @@ func evalInfixOperation(leftOperand object.Object, operator string, rightOperand
 		return evalOrExpression(leftOperand, rightOperand)
 	case operator == token.IN:
 		return evalInExpression(leftOperand, rightOperand)
+	case operator == token.COALESCE:
+		return rightOperand
+	case operator == token.EQ:
+		return nativeToBooleanObject(objectsEqual(leftOperand, rightOperand))
+	case operator == token.NOT_EQ:
+		return nativeToBooleanObject(!objectsEqual(leftOperand, rightOperand))
+	case isRelational(operator) && (leftOperand.Type() == object.NULL_OBJ || rightOperand.Type() == object.NULL_OBJ):
+		return newError("Invalid comparison with NULL: %s %s %s", leftOperand.Type(), operator, rightOperand.Type())
 	case (leftOperand.Type() == object.INTEGER_OBJ || leftOperand.Type() == object.FLOAT_OBJ) && (rightOperand.Type() == object.INTEGER_OBJ || rightOperand.Type() == object.FLOAT_OBJ):
 		return evalArithmeticExpression(leftOperand, operator, rightOperand)
 	case leftOperand.Type() == object.STRING_OBJ && rightOperand.Type() == object.STRING_OBJ:
 		return evalStringOperation(leftOperand, operator, rightOperand)
-	case operator == token.EQ:
-		return nativeToBooleanObject(leftOperand == rightOperand)
-	case operator == token.NOT_EQ:
-		return nativeToBooleanObject(leftOperand != rightOperand)
 	case leftOperand.Type() != rightOperand.Type():
 		return newError("Type mismatch: %s %s %s", leftOperand.Type(), operator, rightOperand.Type())
 	default:
@@ -508,6 +1009,11 @@ func evalInfixOperation(leftOperand object.Object, operator string, rightOperand
 	}
 }
 
+// Helper function to check for relational operator
+func isRelational(operator string) bool {
+	return operator == token.LT || operator == token.LT_EQ || operator == token.GT || operator == token.GT_EQ
+}
+
 // If operand is number, do a minus operation and return the result
 // Else, return invalid operand error
 func evalMinusExpression(operand object.Object) object.Object {
@@ -680,6 +1186,7 @@ func evalFloatIntOperation(leftOperand *object.Float, operator string, rightOper
 }
 
 // If operator is a valid string operator, then perform the operation and return the result
+// Strings are compared lexicographically by their unicode code points
 // Else return unknown operator error
 func evalStringOperation(leftOperand object.Object, operator string, rightOperand object.Object) object.Object {
 	leftValue := leftOperand.(*object.String).Value
@@ -692,6 +1199,14 @@ func evalStringOperation(leftOperand object.Object, operator string, rightOperan
 		return nativeToBooleanObject(leftValue == rightValue)
 	case token.NOT_EQ:
 		return nativeToBooleanObject(leftValue != rightValue)
+	case token.LT:
+		return nativeToBooleanObject(leftValue < rightValue)
+	case token.LT_EQ:
+		return nativeToBooleanObject(leftValue <= rightValue)
+	case token.GT:
+		return nativeToBooleanObject(leftValue > rightValue)
+	case token.GT_EQ:
+		return nativeToBooleanObject(leftValue >= rightValue)
 	default:
 		return newError("Unknown operator: %s %s %s", leftOperand.Type(), operator, rightOperand.Type())
 	}
@@ -727,15 +1242,15 @@ func evalOrExpression(leftOperand object.Object, rightOperand object.Object) obj
 func evalInExpression(leftOperand object.Object, rightOperand object.Object) object.Object {
 	if iterable, ok := rightOperand.(object.Iterable); ok {
 		if hash, ok := iterable.(*object.Hash); ok {
-			if key, ok := leftOperand.(object.Hashable); ok {
-				if _, exist := hash.Pairs[key.HashKey()]; exist {
+			if key, ok := object.HashKeyOf(leftOperand); ok {
+				if _, exist := hash.Pairs[key]; exist {
 					return TRUE
 				}
 			}
 			return FALSE
 		}
 		for _, element := range iterable.Iter().Elements {
-			if evalInfixOperation(leftOperand, token.EQ, element) == TRUE {
+			if objectsEqual(leftOperand, element) {
 				return TRUE
 			}
 		}
@@ -744,6 +1259,115 @@ func evalInExpression(leftOperand object.Object, rightOperand object.Object) obj
 	return newError("Invalid operand: in %s", rightOperand.Type())
 }
 
+// Checks whether two objects are equal. This is the equality used by ==, != and in
+// Numbers are equal if they have same value, irrespective of being integer or float. ie, 1 == 1.0. See numbersEqual
+// Strings and tuples are compared by value, whereas others are compared by reference
+// NULL is equal only to NULL
+func objectsEqual(leftOperand object.Object, rightOperand object.Object) bool {
+	_, leftNull := leftOperand.(*object.Null)
+	_, rightNull := rightOperand.(*object.Null)
+	if leftNull || rightNull {
+		return leftNull && rightNull
+	}
+	if leftTuple, ok := leftOperand.(*object.Tuple); ok {
+		rightTuple, ok := rightOperand.(*object.Tuple)
+		if !ok || len(leftTuple.Elements) != len(rightTuple.Elements) {
+			return false
+		}
+		for index, element := range leftTuple.Elements {
+			if !objectsEqual(element, rightTuple.Elements[index]) {
+				return false
+			}
+		}
+		return true
+	}
+	if _, ok := toFloat(leftOperand); ok {
+		_, ok := toFloat(rightOperand)
+		return ok && numbersEqual(leftOperand, rightOperand)
+	}
+	if leftString, ok := leftOperand.(*object.String); ok {
+		rightString, ok := rightOperand.(*object.String)
+		return ok && leftString.Value == rightString.Value
+	}
+	return leftOperand == rightOperand
+}
+
+// Checks whether two numbers are equal
+// Integers are compared exactly, so that large integers which round to the same float are not equal
+// Integer is equal to a float only if the float is integral and has the same value, as in the hash keys of numbers
+func numbersEqual(leftOperand object.Object, rightOperand object.Object) bool {
+	leftInteger, leftIsInteger := leftOperand.(*object.Integer)
+	rightInteger, rightIsInteger := rightOperand.(*object.Integer)
+	switch {
+	case leftIsInteger && rightIsInteger:
+		return leftInteger.Value == rightInteger.Value
+	case leftIsInteger:
+		return integerEqualsFloat(leftInteger.Value, rightOperand.(*object.Float).Value)
+	case rightIsInteger:
+		return integerEqualsFloat(rightInteger.Value, leftOperand.(*object.Float).Value)
+	default:
+		return leftOperand.(*object.Float).Value == rightOperand.(*object.Float).Value
+	}
+}
+
+// Helper function to check whether the float has exactly the value of the integer
+func integerEqualsFloat(integer int, float float64) bool {
+	return float == math.Trunc(float) && math.Abs(float) < math.MaxInt64 && int(float) == integer
+}
+
+// Checks whether two objects are structurally equal
+// Arrays and tuples are equal if their elements are deeply equal in order
+// Hashes are equal if they have the same keys, and the values for each key are deeply equal
+// Other objects are compared using objectsEqual
+// compared holds the pairs of containers being compared, so that self-referential containers don't recurse forever
+func objectsDeepEqual(leftOperand object.Object, rightOperand object.Object, compared map[[2]object.Object]bool) bool {
+	switch left := leftOperand.(type) {
+	case *object.Array:
+		right, ok := rightOperand.(*object.Array)
+		return ok && elementsDeepEqual(left, right, left.Elements, right.Elements, compared)
+	case *object.Tuple:
+		right, ok := rightOperand.(*object.Tuple)
+		return ok && elementsDeepEqual(left, right, left.Elements, right.Elements, compared)
+	case *object.Hash:
+		right, ok := rightOperand.(*object.Hash)
+		if !ok || len(left.Pairs) != len(right.Pairs) {
+			return false
+		}
+		pair := [2]object.Object{left, right}
+		if left == right || compared[pair] {
+			return true
+		}
+		compared[pair] = true
+		for key, leftPair := range left.Pairs {
+			rightPair, ok := right.Pairs[key]
+			if !ok || !objectsDeepEqual(leftPair.Value, rightPair.Value, compared) {
+				return false
+			}
+		}
+		return true
+	default:
+		return objectsEqual(leftOperand, rightOperand)
+	}
+}
+
+// Helper function to check whether the elements of two arrays/tuples are deeply equal in order
+func elementsDeepEqual(left, right object.Object, leftElements, rightElements []object.Object, compared map[[2]object.Object]bool) bool {
+	if len(leftElements) != len(rightElements) {
+		return false
+	}
+	pair := [2]object.Object{left, right}
+	if left == right || compared[pair] {
+		return true
+	}
+	compared[pair] = true
+	for index, element := range leftElements {
+		if !objectsDeepEqual(element, rightElements[index], compared) {
+			return false
+		}
+	}
+	return true
+}
+
 // Evaluate all the array elements
 // If there was only 1 valid argument and it evaluated to error, then return the err
 // Else, create and return Array object
@@ -755,6 +1379,16 @@ func evalArrayLiteral(array *ast.ArrayLiteral, env *object.Environment) object.O
 	return &object.Array{Elements: elements}
 }
 
+// Evaluate all the tuple elements
+// If there was an error, then return it. Otherwise, return the tuple object
+func evalTupleLiteral(tuple *ast.TupleLiteral, env *object.Environment) object.Object {
+	elements := evalExpressions(tuple.Elements, env)
+	if len(elements) == 1 && isError(elements[0]) {
+		return elements[0]
+	}
+	return &object.Tuple{Elements: elements}
+}
+
 // Create a map - internal data structure for hash
 // Loop through each key, value
 // If key was evaluated to error/ it is not hash-able, then return error
@@ -769,7 +1403,7 @@ func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Obje
 		if isError(key) {
 			return key
 		}
-		hashKey, ok := key.(object.Hashable)
+		hashKey, ok := object.HashKeyOf(key)
 		if !ok {
 			return newError("Key: %s cannot be hashed", key.Type())
 		}
@@ -777,23 +1411,98 @@ func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Obje
 		if isError(value) {
 			return value
 		}
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{Key: key, Value: value}
+		pairs[hashKey] = object.HashPair{Key: key, Value: value}
 	}
 	return &object.Hash{Pairs: pairs}
 }
 
+// Evaluates an array/hash comprehension
+// If object is not iterable, then return error
+// Else, provision a local environment and set each element of the iterable in it
+// Skip the elements for which the guard (if any) is falsy
+// Evaluate the value (and key) for rest of the elements and collect them into an array (or hash)
+func evalComprehensionExpression(comprehension *ast.ComprehensionExpression, env *object.Environment) object.Object {
+	iterObject := Eval(comprehension.Iterator, env)
+	if isError(iterObject) {
+		return iterObject
+	}
+	iterable, ok := iterObject.(object.Iterable)
+	if !ok {
+		return newError("%s: is not iterable", iterObject.Type())
+	}
+	if generator, ok := loopGenerator(comprehension.Iterator, iterable); ok {
+		defer generator.Close()
+	}
+	localEnv := object.NewEnclosedEnvironment(env)
+	elements := []object.Object{}
+	pairs := make(map[object.HashKey]object.HashPair)
+	nextElement := elementIterator(iterable)
+	for iteration := 0; ; iteration++ {
+		item, ok := nextElement()
+		if !ok {
+			break
+		}
+		if isError(item) {
+			return item
+		}
+		if iterationLimitExceeded(env, iteration) {
+			return newError("Iteration limit exceeded")
+		}
+		localEnv.Set(comprehension.Element.Value, item)
+		if comprehension.Guard != nil {
+			guard := Eval(comprehension.Guard, localEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTrue(guard) {
+				continue
+			}
+		}
+		var key object.Object
+		if comprehension.Key != nil {
+			key = Eval(comprehension.Key, localEnv)
+			if isError(key) {
+				return key
+			}
+		}
+		value := Eval(comprehension.Value, localEnv)
+		if isError(value) {
+			return value
+		}
+		if comprehension.Key == nil {
+			elements = append(elements, value)
+			continue
+		}
+		hashKey, ok := object.HashKeyOf(key)
+		if !ok {
+			return newError("Key: %s cannot be hashed", key.Type())
+		}
+		pairs[hashKey] = object.HashPair{Key: key, Value: value}
+	}
+	if comprehension.Key != nil {
+		return &object.Hash{Pairs: pairs}
+	}
+	return &object.Array{Elements: elements}
+}
+
+// Error for an identifier which is not set in the environment chain, with its name and location
+// Tools like the analyzer use it to recognize the error
+const UndefinedIdentifierError = "Identifier: %s not found at %s"
+
 // If identifier is set in environment chain, then return it
-// Else, check in built-ins and return it, if present
+// Else, check in built-ins, and return it, if present. Scope built-ins are bound to the current environment
 // Otherwise, return unknown identifier error
 func evalIdentifier(identifier *ast.Identifier, env *object.Environment) object.Object {
 	if value, ok := env.Get(identifier.Value); ok {
 		return value
 	}
 	if builtin, ok := builtins[identifier.Value]; ok {
+		if function, ok := builtin.(scopeFunction); ok {
+			return bindScope(function, env)
+		}
 		return builtin
 	}
-	return newError("Identifier: %s not found at %s", identifier.Value, identifier.Token.Location)
+	return newError(UndefinedIdentifierError, identifier.Value, identifier.Token.Location)
 }
 
 // Convert boolean value to boolean object
@@ -806,8 +1515,11 @@ func nativeToBooleanObject(value bool) *object.Boolean {
 }
 
 // Check whether object is having truthy value or not
+// null is always falsy
 func isTrue(obj object.Object) bool {
 	switch variable := obj.(type) {
+	case *object.Null:
+		return false
 	case *object.Boolean:
 		return variable.Value
 	case *object.Integer:
@@ -826,6 +1538,10 @@ func isTrue(obj object.Object) bool {
 		if len(variable.Elements) > 0 {
 			return true
 		}
+	case *object.Tuple:
+		if len(variable.Elements) > 0 {
+			return true
+		}
 	case *object.Hash:
 		if len(variable.Pairs) > 0 {
 			return true
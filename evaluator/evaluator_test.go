@@ -0,0 +1,388 @@
+package evaluator
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mochatek/frolang/ast"
+	"github.com/mochatek/frolang/lexer"
+	"github.com/mochatek/frolang/object"
+	"github.com/mochatek/frolang/parser"
+)
+
+// Output of print is discarded, as the tests check the results
+func TestMain(m *testing.M) {
+	Stdout = io.Discard
+	os.Exit(m.Run())
+}
+
+// Parses and evaluates the input in a new environment
+// Fails the test if the input cannot be parsed
+func testEval(t *testing.T, input string) object.Object {
+	t.Helper()
+	par := parser.New(lexer.New(input))
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		t.Fatalf("Parse errors for %q: %v", input, par.Errors())
+	}
+	return SafeEval(program, object.NewEnvironment())
+}
+
+// Evaluates the input like testEval, in an environment having the options
+func testEvalWithOptions(t *testing.T, input string, options *Options) object.Object {
+	t.Helper()
+	par := parser.New(lexer.New(input))
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		t.Fatalf("Parse errors for %q: %v", input, par.Errors())
+	}
+	env := object.NewEnvironment()
+	env.SetOptions(options)
+	return SafeEval(program, env)
+}
+
+// Returns the inspected form of the object, or nil if there is no object
+func inspect(obj object.Object) string {
+	if obj == nil {
+		return "nil"
+	}
+	return obj.Inspect()
+}
+
+// Evaluates the input of each test and compares the inspected result with the expected one
+func runEvalTests(t *testing.T, tests []struct{ input, expected string }) {
+	t.Helper()
+	for _, test := range tests {
+		if result := inspect(testEval(t, test.input)); result != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.input, test.expected, result)
+		}
+	}
+}
+
+func TestDeferStatement(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let log = []; let f = fn() { defer fn() { log = push(log, 1) }(); defer fn() { log = push(log, 2) }(); 0 }; f(); log`, "[2, 1]"},
+		{`let log = []; let f = fn() { defer fn() { log = push(log, "done") }(); 1 / 0 }; try { f() } catch (e) { }` + "\nlog", "[done]"},
+	})
+}
+
+// A panic while evaluating is reported as an error, so that the caller keeps running
+func TestSafeEvalRecovers(t *testing.T) {
+	node := &ast.InfixExpression{Operator: "+", Left: &ast.IntegerLiteral{Value: 1}}
+	if result := inspect(SafeEval(node, object.NewEnvironment())); !strings.HasPrefix(result, "EVAL ERROR: ") {
+		t.Errorf("Expected an error, got %s", result)
+	}
+	if result := inspect(testEval(t, `1 + 1`)); result != "2" {
+		t.Errorf("Expected evaluation to work after a panic, got %s", result)
+	}
+}
+
+// Missing nodes from failed parses are reported as errors instead of panicking
+func TestEvalIncompleteExpression(t *testing.T) {
+	if result := inspect(Eval(nil, object.NewEnvironment())); result != "EVAL ERROR: Cannot evaluate an incomplete expression" {
+		t.Errorf("Expected incomplete expression error, got %s", result)
+	}
+	node := &ast.PrefixExpression{Operator: "-"}
+	if result := inspect(Eval(node, object.NewEnvironment())); result != "EVAL ERROR: Cannot evaluate an incomplete expression" {
+		t.Errorf("Expected incomplete expression error, got %s", result)
+	}
+}
+
+// Iteration limit is an option of each run, so that runs with different limits can be evaluated at the same time
+func TestMaxIterations(t *testing.T) {
+	tests := []struct {
+		maxIterations int
+		input         string
+		expected      string
+	}{
+		{100, "let n = 0; while n < 50 { n = n + 1 }\nn", "50"},
+		{100, `while true { }`, "EVAL ERROR: Iteration limit exceeded"},
+		{100, "let n = 0; for x in range(0, 200) { n = x }\nn", "EVAL ERROR: Iteration limit exceeded"},
+		{100, "let n = 0; let task = spawn(fn() { for x in range(0, 200) { n = x } }); wait(task)", "EVAL ERROR: Iteration limit exceeded"},
+		{1000, "let n = 0; for x in range(0, 200) { n = x }\nn", "199"},
+		{0, "let n = 0; for x in range(0, 2000) { n = x }\nn", "1999"},
+	}
+	results := make([]string, len(tests))
+	var wait sync.WaitGroup
+	for index, test := range tests {
+		wait.Add(1)
+		go func(index int, maxIterations int, input string) {
+			defer wait.Done()
+			program := parser.New(lexer.New(input)).ParseProgram()
+			env := object.NewEnvironment()
+			env.SetOptions(&Options{MaxIterations: maxIterations})
+			results[index] = inspect(Eval(program, env))
+		}(index, test.maxIterations, test.input)
+	}
+	wait.Wait()
+	for index, test := range tests {
+		if results[index] != test.expected {
+			t.Errorf("%q with limit %d: expected %q, got %q", test.input, test.maxIterations, test.expected, results[index])
+		}
+	}
+}
+
+func TestFunctionName(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let add = fn(a, b) { a + b }; add`, "fn add(a, b){\na + b\n}"},
+		{`fn(a) { a }`, "fn(a){\na\n}"},
+	})
+}
+
+func TestHashLiteralKeys(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let name = "fro"; {name}["name"]`, "fro"},
+		{`{1 + 1: "two"}[2]`, "two"},
+		{`let x = 1; let h = {x, x + 1: "two", "k": 3}; [h["x"], h[2], h["k"]]`, "[1, two, 3]"},
+	})
+}
+
+func TestSpreadOperator(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let middle = [2, 3]; [1, ...middle, 4]`, "[1, 2, 3, 4]"},
+		{`[...[], ...[1]]`, "[1]"},
+		{`let add = fn(a, b, c) { a + b + c }; add(1, ...[2, 3])`, "6"},
+		{`[...5]`, "EVAL ERROR: INTEGER: cannot be spread at 1:2"},
+	})
+}
+
+func TestSliceAssignment(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let items = [1, 2, 3, 4]; items[1:3] = ["x"]; items`, "[1, x, 4]"},
+		{`let items = [1, 2]; items[2:2] = [3, 4]; items`, "[1, 2, 3, 4]"},
+		{`let items = [1, 2, 3]; let copy = items; items[0:1] = []; [items, copy]`, "[[2, 3], [1, 2, 3]]"},
+	})
+}
+
+func TestSliceExpression(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let a = [1, 2, 3, 4, 5]; a[:2]`, "[1, 2]"},
+		{`let a = [1, 2, 3, 4, 5]; a[1:]`, "[2, 3, 4, 5]"},
+		{`let a = [1, 2, 3, 4, 5]; a[::2]`, "[1, 3, 5]"},
+		{`let a = [1, 2, 3, 4, 5]; a[-2:]`, "[4, 5]"},
+		{`let a = [1, 2, 3, 4, 5]; a[:-3]`, "[1, 2]"},
+		{`let a = [1, 2, 3, 4, 5]; a[::-1]`, "[5, 4, 3, 2, 1]"},
+		{`"frolang"[1:3]`, "ro"},
+	})
+}
+
+func TestChainedComparison(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let x = 5; 1 < x < 10`, "true"},
+		{`let x = 20; 1 < x < 10`, "false"},
+		{`let x = 0; 1 < x < 10`, "false"},
+		{`let n = 0; let f = fn() { n = n + 1; 5 }; 1 < f() < 10; n`, "1"},
+	})
+}
+
+func TestPostfixExpression(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let i = 0; i++; i`, "1"},
+		{`let i = 0; i++`, "0"},
+		{`let i = 5; let j = i--; [i, j]`, "[4, 5]"},
+		{`let a = 1.5; a++; a`, "2.50"},
+		{"let i = 0; while i < 3 { i++ }\ni", "3"},
+		{`let s = "a"; s++`, "EVAL ERROR: Cannot apply ++ to STRING at 1:15"},
+	})
+}
+
+// ++ and -- are postfix only right after a variable, when no operand follows them
+func TestPostfixAndMinus(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let a = 5; let b = 2; a--b`, "7"},
+		{`let a = 5; let b = 2; a-- b`, "7"},
+		{`let a = 5; a-- - 1`, "4"},
+		{`let a = 5; let b = a-- - 1; [a, b]`, "[4, 4]"},
+	})
+}
+
+func TestMatchExpression(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`match [1, [2, 3]] { [a, [b, c]] => a + b + c, _ => 0 }`, "6"},
+		{`match [1, 2, 3] { [first, ...rest] => rest, _ => 0 }`, "[2, 3]"},
+		{`match [1] { [a, b] => a + b, _ => "no match" }`, "no match"},
+		{`match {"name": "fro", "age": 1} { {"name": n} => n, _ => "none" }`, "fro"},
+		{`match {"age": 1} { {"name": n} => n, _ => "none" }`, "none"},
+	})
+}
+
+func TestGuards(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{"let r = []; for x in [1, 2, 3, 4] if x > 2 { r = push(r, x) }\nr", "[3, 4]"},
+		{"let r = []; for x in [1, 2] if false { r = push(r, x) }\nr", "[]"},
+		{`match 5 { x if x > 3 => "big", _ => "small" }`, "big"},
+		{`match 2 { x if x > 3 => "big", _ => "small" }`, "small"},
+	})
+}
+
+func TestComprehensions(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[x * x for x in range(0, 6) if x > 2]`, "[9, 16, 25]"},
+		{`[x for x in []]`, "[]"},
+		{`let squares = {x: x * x for x in [1, 2]}; [squares[1], squares[2]]`, "[1, 4]"},
+	})
+}
+
+func TestOperatorOverloading(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let v = {"value": 1, "__add__": fn(a, b) { {"value": a["value"] + b["value"]} }}; (v + v)["value"]`, "2"},
+		{`{"value": 1} + {"value": 2}`, "EVAL ERROR: Unknown operator: HASH + HASH"},
+	})
+}
+
+func TestNumericEquality(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[1 == 1.0, 1 != 1.0, 2 >= 2.0, 1 < 1.5, 1.5 > 1]`, "[true, false, true, true, true]"},
+		{`[1.0 in [1, 2], 3 in [1.5, 3.0]]`, "[true, true]"},
+		{`{1: "one"}[1.0]`, "one"},
+	})
+}
+
+// Integers are compared exactly, even when they cannot be represented as float64
+func TestLargeIntegerEquality(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`9007199254740993 == 9007199254740992`, "false"},
+		{`9007199254740992 < 9007199254740993`, "true"},
+	})
+}
+
+func TestArrayHashKeys(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let h = {[1, 2]: "pair"}; h[[1, 2]]`, "pair"},
+		{`let key = [1, "a"]; let h = {key: 1}; h[[1, "a"]]`, "1"},
+		{`{[[1]]: 1}[[[1]]]`, "1"},
+		{`{[{}]: 1}`, "EVAL ERROR: Key: ARRAY cannot be hashed"},
+	})
+}
+
+func TestTupleLiterals(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`(1, 2)`, "(1, 2)"},
+		{`(1,)`, "(1,)"},
+		{`type((1,))`, "TUPLE"},
+		{`let t = (1, 2); let h = {t: "tuple"}; h[(1, 2)]`, "tuple"},
+		{`let x = 3; (x)`, "3"},
+		{`(1 + 2) * 3`, "9"},
+	})
+}
+
+func TestNullComparison(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let none = {}["none"]; none == none`, "true"},
+		{`let none = {}["none"]; none == 0`, "false"},
+		{`let none = {}["none"]; none != 0`, "true"},
+		{`let none = {}["none"]; none < 1`, "EVAL ERROR: Invalid comparison with NULL: NULL < INTEGER"},
+	})
+}
+
+func TestNullIsFalsy(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let none = {}["none"]; if none { 1 } else { 2 }`, "2"},
+		{`let none = {}["none"]; !none`, "true"},
+		{`let none = {}["none"]; none | true`, "true"},
+		{`let none = {}["none"]; none & true`, "null"},
+	})
+}
+
+func TestCoalesceExpression(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let none = {}["none"]; none ?? 5`, "5"},
+		{`0 ?? 5`, "0"},
+		{`false ?? 5`, "false"},
+		{`"" ?? 5`, ""},
+		{`let none = {}["none"]; none ?? none ?? 3`, "3"},
+		{`let x = 1; 2 ?? (x = 5); x`, "1"},
+		{`{"a": 1}["b"] ?? "none"`, "none"},
+	})
+}
+
+// Missing operands are treated as null, instead of crashing the evaluation
+func TestComparisonWithMissingValue(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`print() == print()`, "true"},
+		{`print() == 0`, "false"},
+		{`print() < 1`, "EVAL ERROR: Invalid comparison with NULL: NULL < INTEGER"},
+		{`1 < 2 < print()`, "EVAL ERROR: Invalid comparison with NULL: INTEGER < NULL"},
+		{`print() + 1`, "EVAL ERROR: Type mismatch: NULL + INTEGER"},
+	})
+}
+
+func TestOptionalChaining(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let user = {"address": {"city": "Kochi"}}; user?.address?.city`, "Kochi"},
+		{`let user = {"address": {"city": "Kochi"}}; user?.profile?.city`, "null"},
+		{`let user = {"tags": ["a"]}; user?.tags?[0]`, "a"},
+		{`let user = {}; user?.tags?[0]`, "null"},
+		{`let none = {}["none"]; none.a`, "EVAL ERROR: Member access not supported for: NULL.a"},
+	})
+}
+
+// Optional access on a missing value gives null for index, slice and member access alike
+func TestOptionalChainingOnMissingValue(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let user = {}; user?.tags?[0:1]`, "null"},
+		{`print()?.a`, "null"},
+		{`print()?[0]`, "null"},
+		{`print()?[0:1]`, "null"},
+		{`print()[0]`, "EVAL ERROR: Index operation not supported for: NULL[INTEGER]"},
+	})
+}
+
+func TestNumberSuffixes(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`5f + 1`, "6.00"},
+		{`[type(5f), type(5i), type(5)]`, "[FLOAT, INTEGER, INTEGER]"},
+	})
+}
+
+// Literals, arithmetic and type agree on integers and floats
+func TestNumberTypes(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[type(1), type(1.5), type(1 + 1), type(1 + 1.5), type(1.5 * 2)]`, "[INTEGER, FLOAT, INTEGER, FLOAT, FLOAT]"},
+		{`[type(4 / 2), 7 / 2, type(7.0 / 2)]`, "[INTEGER, 3, FLOAT]"},
+	})
+}
+
+// Top-level return ends the script with its value
+func TestTopLevelReturn(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let x = 1; return x + 1; x = 5`, "2"},
+		{"for x in [1, 2, 3] { if x == 2 { return x * 10 } }\n0", "20"},
+	})
+}
+
+func TestJumpOutsideLoop(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`break`, "EVAL ERROR: 'break' used outside of a loop"},
+		{`continue`, "EVAL ERROR: 'continue' used outside of a loop"},
+		{"let n = 0; for x in [1, 2, 3] { if x == 2 { break }; n = x }\nn", "1"},
+	})
+}
+
+func TestBareReturn(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let f = fn(x) { if x { return }; 1 }; [f(true), f(false)]`, "[null, 1]"},
+		{`let f = fn() { return }; type(f())`, "NULL"},
+	})
+}
+
+func TestAssignExpression(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let x = 1; x = 2`, "2"},
+		{`let x = 0; let y = (x = 5) + 1; [x, y]`, "[5, 6]"},
+		{`let x = 0; let y = 0; x = y = 3; [x, y]`, "[3, 3]"},
+		{"let line = 0; let n = 0; while (line = line + 1) < 4 { n = n + line }\nn", "6"},
+	})
+}
+
+// Errors show the calls they passed through, innermost first
+func TestErrorCallChain(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let f = fn() { g() }; let g = fn() { 1 / 0 }; f()`, "EVAL ERROR: Division by 0 is not allowed\n    in g called at 1:17\n    in f called at 1:48"},
+		{`let f = fn() { break }; f()`, "EVAL ERROR: 'break' used outside of a loop\n    in f called at 1:26"},
+		{"let f = fn() { 1 / 0 }; let m = \"\"; try { f() } catch (e) { m = m + e }\nm", "Division by 0 is not allowed"},
+	})
+}
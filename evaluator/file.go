@@ -0,0 +1,178 @@
+package evaluator
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Flags to open a file for each of the supported modes
+var fileModes = map[string]int{
+	"r": os.O_RDONLY,
+	"w": os.O_WRONLY | os.O_CREATE | os.O_TRUNC,
+	"a": os.O_WRONLY | os.O_CREATE | os.O_APPEND,
+}
+
+// Methods that can be called on a file object. Example: file.readLine()
+var fileMethods = map[string]func(file *object.File, arguments ...object.Object) object.Object{
+	"readLine": fileReadLine,
+	"read":     fileRead,
+	"write":    fileWrite,
+	"close":    fileClose,
+}
+
+// Files opened by the program, which are closed by CloseFiles
+var openFiles = struct {
+	sync.Mutex
+	files []*object.File
+}{}
+
+// Closes all the files opened by the program, which are not closed yet
+// Should be called once the evaluation is over
+func CloseFiles() {
+	openFiles.Lock()
+	defer openFiles.Unlock()
+	for _, file := range openFiles.files {
+		file.Close()
+	}
+	openFiles.files = nil
+}
+
+// Keeps track of the opened file, and forgets the files which are already closed
+func trackFile(file *object.File) {
+	openFiles.Lock()
+	defer openFiles.Unlock()
+	files := []*object.File{}
+	for _, openFile := range openFiles.files {
+		if !openFile.Closed {
+			files = append(files, openFile)
+		}
+	}
+	openFiles.files = append(files, file)
+}
+
+// Opens the file at path and returns the file object
+// Mode can be "r" (read), "w" (write) or "a" (append). File is opened for reading, if mode is not supplied
+func open(arguments ...object.Object) object.Object {
+	if 1 > len(arguments) || len(arguments) > 2 {
+		return newError("Wrong number of arguments. Got=%d want=(min:1, max: 2)", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ {
+		return newError("Path to open must be STRING. Got %s", arguments[0].Type())
+	}
+	mode := "r"
+	if len(arguments) == 2 {
+		if arguments[1].Type() != object.STRING_OBJ {
+			return newError("Mode to open must be STRING. Got %s", arguments[1].Type())
+		}
+		mode = arguments[1].(*object.String).Value
+	}
+	flag, ok := fileModes[mode]
+	if !ok {
+		return newError("Mode to open must be one of r, w, a. Got %s", mode)
+	}
+	path := arguments[0].(*object.String).Value
+	handle, err := os.OpenFile(path, flag, 0644)
+	if err != nil {
+		return newError("Cannot open file: %s", err)
+	}
+	file := &object.File{Path: path, Mode: mode, Handle: handle}
+	if mode == "r" {
+		file.Reader = bufio.NewReader(handle)
+	}
+	trackFile(file)
+	return file
+}
+
+// Returns the method of file object as a builtin bound to that file
+func fileMethod(file *object.File, name string) (object.Object, bool) {
+	method, ok := fileMethods[name]
+	if !ok {
+		return nil, false
+	}
+	return &object.Builtin{Fn: func(arguments ...object.Object) object.Object {
+		return method(file, arguments...)
+	}}, true
+}
+
+// Helper function to validate that a file can be read
+func checkReadable(file *object.File) *object.Error {
+	if file.Closed {
+		return newError("Cannot read from closed file: %s", file.Path)
+	}
+	if file.Reader == nil {
+		return newError("File: %s is not opened for reading", file.Path)
+	}
+	return nil
+}
+
+// Reads the next line from file and returns it without the line ending
+// Returns NULL if end of file is reached
+func fileReadLine(file *object.File, arguments ...object.Object) object.Object {
+	if len(arguments) != 0 {
+		return newError("Wrong number of arguments. Got=%d want=0", len(arguments))
+	}
+	if err := checkReadable(file); err != nil {
+		return err
+	}
+	line, err := file.Reader.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return NULL
+	}
+	if err != nil && err != io.EOF {
+		return newError("Cannot read from file: %s", err)
+	}
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	return &object.String{Value: line}
+}
+
+// Reads the rest of the file and returns it
+func fileRead(file *object.File, arguments ...object.Object) object.Object {
+	if len(arguments) != 0 {
+		return newError("Wrong number of arguments. Got=%d want=0", len(arguments))
+	}
+	if err := checkReadable(file); err != nil {
+		return err
+	}
+	content, err := io.ReadAll(file.Reader)
+	if err != nil {
+		return newError("Cannot read from file: %s", err)
+	}
+	return &object.String{Value: string(content)}
+}
+
+// Writes the string to file
+func fileWrite(file *object.File, arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ {
+		return newError("Argument to write must be STRING. Got %s", arguments[0].Type())
+	}
+	if file.Closed {
+		return newError("Cannot write to closed file: %s", file.Path)
+	}
+	if file.Mode == "r" {
+		return newError("File: %s is not opened for writing", file.Path)
+	}
+	if _, err := file.Handle.WriteString(arguments[0].(*object.String).Value); err != nil {
+		return newError("Cannot write to file: %s", err)
+	}
+	return nil
+}
+
+// Closes the file
+func fileClose(file *object.File, arguments ...object.Object) object.Object {
+	if len(arguments) != 0 {
+		return newError("Wrong number of arguments. Got=%d want=0", len(arguments))
+	}
+	if err := file.Close(); err != nil {
+		return newError("Cannot close file: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package evaluator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Resource of with statement is closed after the block, even if the block results in error
+func TestWithStatement(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("one"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runEvalTests(t, []struct{ input, expected string }{
+		{"let handle = 0; with open(\"" + path + "\") as file { handle = file }\nhandle", "File(" + path + ", closed)"},
+		{"let handle = 0; try { with open(\"" + path + "\") as file { handle = file; 1 / 0 } } catch (e) { }\nhandle", "File(" + path + ", closed)"},
+		{`let f = fn() { with open("` + path + `") as file { return 1 } }; f()`, "1"},
+		{`with 5 as x { x }`, "EVAL ERROR: INTEGER: is not a resource"},
+	})
+}
+
+func TestFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("one\ntwo\r\nthree"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseFiles()
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let file = open("` + path + `"); [file.readLine(), file.readLine(), file.readLine(), file.readLine()]`, "[one, two, three, null]"},
+		{`with open("` + path + `") as file { file.read() }`, "one\ntwo\r\nthree"},
+		{"let handle = 0; with open(\"" + path + "\") as file { handle = file }\nhandle.read()", "EVAL ERROR: Cannot read from closed file: " + path},
+		{`open("` + path + `", "x")`, "EVAL ERROR: Mode to open must be one of r, w, a. Got x"},
+	})
+
+	copyPath := filepath.Join(filepath.Dir(path), "copy.txt")
+	testEval(t, `with open("`+copyPath+`", "w") as file { file.write("a"); file.write("b") }`)
+	testEval(t, `with open("`+copyPath+`", "a") as file { file.write("c") }`)
+	if data, _ := os.ReadFile(copyPath); string(data) != "abc" {
+		t.Errorf("Expected abc to be written, got %q", data)
+	}
+}
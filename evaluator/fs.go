@@ -0,0 +1,85 @@
+package evaluator
+
+import (
+	"os"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Builtin fs module for working with the file system
+// Example: import "fs"; fs.listDir(".")
+var fsModule = &object.Module{
+	Name: "fs",
+	Members: map[string]object.Object{
+		"listDir": &object.Builtin{Fn: listDir},
+		"exists":  &object.Builtin{Fn: exists},
+		"isDir":   &object.Builtin{Fn: isDir},
+		"remove":  &object.Builtin{Fn: remove},
+	},
+}
+
+// Returns an array of names of the entries in a directory, sorted by name
+func listDir(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ {
+		return newError("Argument to listDir must be STRING. Got %s", arguments[0].Type())
+	}
+	entries, err := os.ReadDir(arguments[0].(*object.String).Value)
+	if err != nil {
+		return newError("Cannot list directory: %s", err)
+	}
+	elements := make([]object.Object, len(entries), len(entries))
+	for idx, entry := range entries {
+		elements[idx] = &object.String{Value: entry.Name()}
+	}
+	return &object.Array{Elements: elements}
+}
+
+// Returns whether a file or directory exists at the path
+func exists(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ {
+		return newError("Argument to exists must be STRING. Got %s", arguments[0].Type())
+	}
+	_, err := os.Stat(arguments[0].(*object.String).Value)
+	if err != nil && !os.IsNotExist(err) {
+		return newError("Cannot check existence: %s", err)
+	}
+	return nativeToBooleanObject(err == nil)
+}
+
+// Returns whether the path is an existing directory
+func isDir(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ {
+		return newError("Argument to isDir must be STRING. Got %s", arguments[0].Type())
+	}
+	info, err := os.Stat(arguments[0].(*object.String).Value)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return FALSE
+		}
+		return newError("Cannot check directory: %s", err)
+	}
+	return nativeToBooleanObject(info.IsDir())
+}
+
+// Removes the file or empty directory at the path
+func remove(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ {
+		return newError("Argument to remove must be STRING. Got %s", arguments[0].Type())
+	}
+	if err := os.Remove(arguments[0].(*object.String).Value); err != nil {
+		return newError("Cannot remove: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,384 @@
+package evaluator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mochatek/frolang/object"
+	"github.com/mochatek/frolang/token"
+)
+
+// Calls the function with the elements of the array as its arguments
+// Returns the result of the function call
+func apply(arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	if err := checkCallable("apply", "First", arguments[0]); err != nil {
+		return err
+	}
+	array, ok := arguments[1].(*object.Array)
+	if !ok {
+		return newError("Second argument to apply must be ARRAY. Got %s", arguments[1].Type())
+	}
+	return applyFunction(arguments[0], array.Elements)
+}
+
+// Returns a builtin which finds the element of an array having the extreme key
+// Key of each element is found by calling the key function with that element
+// An element replaces the current extreme if its key compares true against the current key using the operator
+// First element wins among elements with equal keys. Empty array results in error, like that of max and min
+func extremeBy(name string, operator string) func(arguments ...object.Object) object.Object {
+	return func(arguments ...object.Object) object.Object {
+		if len(arguments) != 2 {
+			return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+		}
+		array, ok := arguments[0].(*object.Array)
+		if !ok {
+			return newError("First argument to %s must be ARRAY. Got %s", name, arguments[0].Type())
+		}
+		if err := checkCallable(name, "Second", arguments[1]); err != nil {
+			return err
+		}
+
+		if len(array.Elements) == 0 {
+			return newError("Cannot find %s of an empty array", name)
+		}
+		var extreme, extremeKey object.Object
+		for _, element := range array.Elements {
+			key := applyFunction(arguments[1], []object.Object{element})
+			if isError(key) {
+				return key
+			}
+			if extreme == nil {
+				extreme, extremeKey = element, key
+				continue
+			}
+			comparison := evalInfixOperation(key, operator, extremeKey)
+			if isError(comparison) {
+				return newError("Keys of %s must be comparable. %s", name, comparison.(*object.Error).Message)
+			}
+			if comparison == TRUE {
+				extreme, extremeKey = element, key
+			}
+		}
+		return extreme
+	}
+}
+
+// Returns a builtin which finds the extreme among the elements of an array, or among its arguments
+// Elements are compared using the operator, just like the comparison operators. So strings are compared lexicographically
+// First element wins among equal elements. Empty array results in error
+// Though it doesn't call user functions, comparison may call an overloaded operator
+func extreme(name string, operator string) func(arguments ...object.Object) object.Object {
+	return func(arguments ...object.Object) object.Object {
+		elements := arguments
+		if len(arguments) == 1 {
+			array, ok := arguments[0].(*object.Array)
+			if !ok {
+				return newError("Argument to %s must be ARRAY. Got %s", name, arguments[0].Type())
+			}
+			elements = array.Elements
+		}
+		if len(elements) == 0 {
+			return newError("Cannot find %s of an empty array", name)
+		}
+		result := elements[0]
+		for _, element := range elements[1:] {
+			comparison := evalInfixOperation(element, operator, result)
+			if isError(comparison) {
+				return newError("Elements of %s must be comparable. %s", name, comparison.(*object.Error).Message)
+			}
+			if comparison == TRUE {
+				result = element
+			}
+		}
+		return result
+	}
+}
+
+// Returns a new array with the elements of the array in ascending order
+// Elements are compared using <, just like the comparison operators. Equal elements keep their original order
+// Options hash may contain:
+// ignoreCase - If true, then strings are compared after converting them to lower case
+// reverse - If true, then elements are sorted in descending order
+func sorted(arguments ...object.Object) object.Object {
+	if 1 > len(arguments) || len(arguments) > 2 {
+		return newError("Wrong number of arguments. Got=%d want=(min:1, max: 2)", len(arguments))
+	}
+	array, ok := arguments[0].(*object.Array)
+	if !ok {
+		return newError("First argument to sort must be ARRAY. Got %s", arguments[0].Type())
+	}
+	options, err := booleanOptions("sort", arguments[1:], "ignoreCase", "reverse")
+	if err != nil {
+		return err
+	}
+	ignoreCase, reverse := options["ignoreCase"], options["reverse"]
+
+	sortKey := func(element object.Object) object.Object {
+		if str, ok := element.(*object.String); ok && ignoreCase {
+			return &object.String{Value: strings.ToLower(str.Value)}
+		}
+		return element
+	}
+	elements := make([]object.Object, len(array.Elements), len(array.Elements))
+	copy(elements, array.Elements)
+	var sortErr object.Object
+	sort.SliceStable(elements, func(i, j int) bool {
+		if sortErr != nil {
+			return false
+		}
+		left, right := sortKey(elements[i]), sortKey(elements[j])
+		if reverse {
+			left, right = right, left
+		}
+		comparison := evalInfixOperation(left, token.LT, right)
+		if isError(comparison) {
+			sortErr = newError("Elements of sort must be comparable. %s", comparison.(*object.Error).Message)
+			return false
+		}
+		return comparison == TRUE
+	})
+	if sortErr != nil {
+		return sortErr
+	}
+	return &object.Array{Elements: elements}
+}
+
+// Returns the boolean options in the optional hash argument of the builtin
+// Options which are not supplied are false. Returns error for unknown options and non-boolean values
+func booleanOptions(name string, arguments []object.Object, names ...string) (map[string]bool, *object.Error) {
+	options := make(map[string]bool, len(names))
+	if len(arguments) == 0 {
+		return options, nil
+	}
+	hash, ok := arguments[0].(*object.Hash)
+	if !ok {
+		return nil, newError("Options to %s must be HASH. Got %s", name, arguments[0].Type())
+	}
+	for _, pair := range hash.Pairs {
+		known := false
+		for _, option := range names {
+			known = known || (pair.Key.Type() == object.STRING_OBJ && pair.Key.Inspect() == option)
+		}
+		if !known {
+			return nil, newError("Unknown option to %s: %s", name, pair.Key.Inspect())
+		}
+		if pair.Value.Type() != object.BOOLEAN_OBJ {
+			return nil, newError("Option %s to %s must be BOOLEAN. Got %s", pair.Key.Inspect(), name, pair.Value.Type())
+		}
+		options[pair.Key.Inspect()] = pair.Value == TRUE
+	}
+	return options, nil
+}
+
+// Returns the pairs of the hash. If sorted is true, then the pairs are sorted by their keys using <
+// Returns error if the keys cannot be compared with each other
+func hashPairs(name string, hash *object.Hash, sorted bool) ([]object.HashPair, *object.Error) {
+	pairs := make([]object.HashPair, 0, len(hash.Pairs))
+	for _, pair := range hash.Pairs {
+		pairs = append(pairs, pair)
+	}
+	if !sorted {
+		return pairs, nil
+	}
+	var sortErr *object.Error
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if sortErr != nil {
+			return false
+		}
+		comparison := evalInfixOperation(pairs[i].Key, token.LT, pairs[j].Key)
+		if isError(comparison) {
+			sortErr = newError("Keys of the hash to %s must be comparable. %s", name, comparison.(*object.Error).Message)
+			return false
+		}
+		return comparison == TRUE
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+	return pairs, nil
+}
+
+// Calls the predicate with each element of the array until it returns a falsy value
+// Returns the index of that element, or the length of the array if the predicate held for all of them
+func whileIndex(name string, arguments []object.Object) (*object.Array, int, object.Object) {
+	if len(arguments) != 2 {
+		return nil, 0, newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	array, ok := arguments[0].(*object.Array)
+	if !ok {
+		return nil, 0, newError("First argument to %s must be ARRAY. Got %s", name, arguments[0].Type())
+	}
+	if err := checkCallable(name, "Second", arguments[1]); err != nil {
+		return nil, 0, err
+	}
+	for index, element := range array.Elements {
+		result := applyFunction(arguments[1], []object.Object{element})
+		if isError(result) {
+			return nil, 0, result
+		}
+		if !isTrue(result) {
+			return array, index, nil
+		}
+	}
+	return array, len(array.Elements), nil
+}
+
+// Returns a new array with the leading elements of the array for which the predicate is truthy
+func takeWhile(arguments ...object.Object) object.Object {
+	array, index, err := whileIndex("takeWhile", arguments)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, index)
+	copy(elements, array.Elements[:index])
+	return &object.Array{Elements: elements}
+}
+
+// Returns a new array without the leading elements of the array for which the predicate is truthy
+func dropWhile(arguments ...object.Object) object.Object {
+	array, index, err := whileIndex("dropWhile", arguments)
+	if err != nil {
+		return err
+	}
+	elements := make([]object.Object, len(array.Elements)-index)
+	copy(elements, array.Elements[index:])
+	return &object.Array{Elements: elements}
+}
+
+// Reduces the array like reduce, but collects every state of the accumulator
+// Function is called with the accumulator and each element, and its result becomes the new accumulator
+// Returned array starts with the initial value, so an empty array gives [initial]
+func scan(arguments ...object.Object) object.Object {
+	if len(arguments) != 3 {
+		return newError("Wrong number of arguments. Got=%d want=3", len(arguments))
+	}
+	array, ok := arguments[0].(*object.Array)
+	if !ok {
+		return newError("First argument to scan must be ARRAY. Got %s", arguments[0].Type())
+	}
+	if err := checkCallable("scan", "Second", arguments[1]); err != nil {
+		return err
+	}
+	accumulator := arguments[2]
+	states := []object.Object{accumulator}
+	for _, element := range array.Elements {
+		accumulator = applyFunction(arguments[1], []object.Object{accumulator, element})
+		if isError(accumulator) {
+			return accumulator
+		}
+		states = append(states, accumulator)
+	}
+	return &object.Array{Elements: states}
+}
+
+// Returns a new array with the results of calling the function with each element of the array
+func mapArray(arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	array, ok := arguments[0].(*object.Array)
+	if !ok {
+		return newError("First argument to map must be ARRAY. Got %s", arguments[0].Type())
+	}
+	if err := checkCallable("map", "Second", arguments[1]); err != nil {
+		return err
+	}
+	elements := make([]object.Object, len(array.Elements), len(array.Elements))
+	for idx, element := range array.Elements {
+		result := applyFunction(arguments[1], []object.Object{element})
+		if isError(result) {
+			return result
+		}
+		elements[idx] = result
+	}
+	return &object.Array{Elements: elements}
+}
+
+// Returns a new array with the elements of the array for which the predicate is truthy
+func filterArray(arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	array, ok := arguments[0].(*object.Array)
+	if !ok {
+		return newError("First argument to filter must be ARRAY. Got %s", arguments[0].Type())
+	}
+	if err := checkCallable("filter", "Second", arguments[1]); err != nil {
+		return err
+	}
+	elements := []object.Object{}
+	for _, element := range array.Elements {
+		result := applyFunction(arguments[1], []object.Object{element})
+		if isError(result) {
+			return result
+		}
+		if isTrue(result) {
+			elements = append(elements, element)
+		}
+	}
+	return &object.Array{Elements: elements}
+}
+
+// Reduces the array to a single value by calling the function with the accumulator and each element
+// If initial value is not supplied, then the first element is used as the initial value
+// Empty array without initial value results in error
+func reduceArray(arguments ...object.Object) object.Object {
+	if 2 > len(arguments) || len(arguments) > 3 {
+		return newError("Wrong number of arguments. Got=%d want=(min:2, max: 3)", len(arguments))
+	}
+	array, ok := arguments[0].(*object.Array)
+	if !ok {
+		return newError("First argument to reduce must be ARRAY. Got %s", arguments[0].Type())
+	}
+	if err := checkCallable("reduce", "Second", arguments[1]); err != nil {
+		return err
+	}
+	elements := array.Elements
+	var accumulator object.Object
+	if len(arguments) == 3 {
+		accumulator = arguments[2]
+	} else {
+		if len(elements) == 0 {
+			return newError("Cannot reduce an empty array without initial value")
+		}
+		accumulator, elements = elements[0], elements[1:]
+	}
+	for _, element := range elements {
+		accumulator = applyFunction(arguments[1], []object.Object{accumulator, element})
+		if isError(accumulator) {
+			return accumulator
+		}
+	}
+	return accumulator
+}
+
+// Calls the function with each element of the array and concatenates the resulting arrays
+// Result which is not an array, including the null of a function giving no value, is added as a single element
+func flatMap(arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	array, ok := arguments[0].(*object.Array)
+	if !ok {
+		return newError("First argument to flatMap must be ARRAY. Got %s", arguments[0].Type())
+	}
+	if err := checkCallable("flatMap", "Second", arguments[1]); err != nil {
+		return err
+	}
+	elements := []object.Object{}
+	for _, element := range array.Elements {
+		result := applyFunction(arguments[1], []object.Object{element})
+		if isError(result) {
+			return result
+		}
+		if resultArray, ok := result.(*object.Array); ok {
+			elements = append(elements, resultArray.Elements...)
+		} else {
+			elements = append(elements, result)
+		}
+	}
+	return &object.Array{Elements: elements}
+}
@@ -0,0 +1,109 @@
+package evaluator
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`apply(fn(a, b) { a + b }, [1, 2])`, "3"},
+		{`let args = push([], "abc"); apply(len, args)`, "3"},
+		{`apply(1, [])`, "EVAL ERROR: First argument to apply must be FUNCTION or BUILTIN. Got INTEGER"},
+	})
+}
+
+func TestFunctionArity(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let add = fn(a, b) { a + b }; add(1, 2)`, "3"},
+		{`let add = fn(a, b) { a + b }; add(...[1, 2])`, "3"},
+		{`let add = fn(a, b) { a + b }; add(1)`, "EVAL ERROR: Wrong number of arguments. Got=1 want=2\n    in add called at 1:34"},
+		{`let add = fn(a, b) { a + b }; add(...[1])`, "EVAL ERROR: Wrong number of arguments. Got=1 want=2\n    in add called at 1:34"},
+		{`let add = fn(a, b) { a + b }; add(1, 2, 3)`, "EVAL ERROR: Wrong number of arguments. Got=3 want=2\n    in add called at 1:34"},
+		{`apply(fn(a, b) { a + b }, [1, 2])`, "3"},
+		{`apply(fn(a, b) { a + b }, [1])`, "EVAL ERROR: Wrong number of arguments. Got=1 want=2"},
+		{`map([1, 2], fn(a, b) { a + b })`, "EVAL ERROR: Wrong number of arguments. Got=1 want=2"},
+		{`let gen = fn(a) { yield a }; gen()`, "EVAL ERROR: Wrong number of arguments. Got=0 want=1\n    in gen called at 1:33"},
+	})
+}
+
+func TestMaxByAndMinBy(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let words = ["a", "abcd", "ab"]; maxBy(words, len)`, "abcd"},
+		{`minBy(["abc", "a", "ab"], len)`, "a"},
+		{`maxBy([1, 3, 2], fn(x) { -x })`, "1"},
+		{`maxBy([1, "a"], fn(x) { x })`, "EVAL ERROR: Keys of maxBy must be comparable. Type mismatch: STRING > INTEGER"},
+		{`maxBy([1, 2], print)`, "EVAL ERROR: Keys of maxBy must be comparable. Invalid comparison with NULL: NULL > NULL"},
+		{`maxBy([], len)`, "EVAL ERROR: Cannot find maxBy of an empty array"},
+		{`minBy([], len)`, "EVAL ERROR: Cannot find minBy of an empty array"},
+		{`max([])`, "EVAL ERROR: Cannot find max of an empty array"},
+	})
+}
+
+func TestTakeWhileAndDropWhile(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[takeWhile([1, 2, 3], fn(x) { x < 2 }), dropWhile([1, 2, 3], fn(x) { x < 2 })]`, "[[1], [2, 3]]"},
+		{`[takeWhile([1], fn(x) { true }), dropWhile([1], fn(x) { true })]`, "[[1], []]"},
+		{`[takeWhile([1], fn(x) { false }), dropWhile([1], fn(x) { false })]`, "[[], [1]]"},
+	})
+}
+
+func TestScan(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`scan([1, 2, 3], fn(sum, x) { sum + x }, 0)`, "[0, 1, 3, 6]"},
+		{`scan([], fn(sum, x) { sum + x }, 0)`, "[0]"},
+		{`scan([1, 2], fn(a, x) { let z = 1 }, 0)`, "[0, null, null]"},
+		{`scan([1, "a"], fn(sum, x) { sum + x }, 0)`, "EVAL ERROR: Type mismatch: INTEGER + STRING"},
+	})
+}
+
+func TestMinAndMax(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[max(["b", "c", "a"]), min(["b", "a"]), max(["apple", "apricot"])]`, "[c, a, apricot]"},
+		{`[max([3, 7, 1]), min(3, 1.5), max(2, 1)]`, "[7, 1.50, 2]"},
+		{`min([])`, "EVAL ERROR: Cannot find min of an empty array"},
+		{`max([])`, "EVAL ERROR: Cannot find max of an empty array"},
+	})
+}
+
+func TestSorted(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`sorted(["b", "B", "a", "A"], {"ignoreCase": true})`, "[a, A, b, B]"},
+		{`sorted(["B", "b", "A", "a"], {"ignoreCase": true})`, "[A, a, B, b]"},
+		{`sorted([3, 1, 2], {"reverse": true})`, "[3, 2, 1]"},
+		{`let a = [3, 1, 2]; let b = sorted(a); [a, b]`, "[[3, 1, 2], [1, 2, 3]]"},
+	})
+}
+
+func TestFlatMap(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`flatMap([1, 2], fn(x) { [x, x * 10] })`, "[1, 10, 2, 20]"},
+		{`flatMap([1, 2], fn(x) { x })`, "[1, 2]"},
+		{`flatMap([1, 2], fn(x) { [] })`, "[]"},
+		{`flatMap([1, 2], print)`, "[null, null]"},
+		{`flatMap([1, "a"], fn(x) { [x + 1] })`, "EVAL ERROR: Type mismatch: STRING + INTEGER"},
+	})
+}
+
+func TestMapFilterReduce(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`map([1, 2, 3], fn(x) { x * 2 })`, "[2, 4, 6]"},
+		{`filter([1, 2, 3], fn(x) { x > 1 })`, "[2, 3]"},
+		{`[reduce([1, 2, 3], fn(sum, x) { sum + x }, 10), reduce([1, 2, 3], fn(sum, x) { sum + x })]`, "[16, 6]"},
+	})
+}
+
+// Callbacks which give no value, like print or a body ending in let, result in null
+func TestCallbackWithoutValue(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`map([1, 2], print)`, "[null, null]"},
+		{`map([1, 2], fn(x) { let y = x })`, "[null, null]"},
+		{`filter([1, 2], print)`, "[]"},
+		{`let f = fn() { let z = 1 }; type(f())`, "NULL"},
+	})
+}
+
+func TestSortedKeysAndValues(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`keys({"b": 1, "c": 3, "a": 2}, {"sorted": true})`, "[a, b, c]"},
+		{`values({"b": 1, "c": 3, "a": 2}, {"sorted": true})`, "[2, 1, 3]"},
+		{`sorted(keys({"b": 1, "a": 2}))`, "[a, b]"},
+	})
+}
@@ -0,0 +1,99 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/mochatek/frolang/ast"
+	"github.com/mochatek/frolang/object"
+)
+
+// Creates a generator for the call of a generator function
+// The body is evaluated on a separate goroutine, only after the first value is requested
+// If the body resulted in error, then that error is produced as the last value
+func newGenerator(function *object.Function, env *object.Environment) *object.Generator {
+	generator := &object.Generator{Values: make(chan object.Object), Resume: make(chan bool, 1)}
+	env.SetGenerator(generator)
+	go func() {
+		defer close(generator.Values)
+		if !<-generator.Resume {
+			return
+		}
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				generator.Values <- newError("Generator failed: %s", fmt.Sprint(recovered))
+			}
+		}()
+		result := unwrapReturnValue(Eval(function.Body, env))
+		if isError(result) {
+			generator.Values <- result
+		}
+	}()
+	return generator
+}
+
+// Evaluates the value and hands it over to the consumer of the generator
+// Then wait till the next value is requested
+// If generator was closed meanwhile, then return from the generator function
+func evalYieldStatement(yieldStatement *ast.YieldStatement, env *object.Environment) object.Object {
+	generator := env.Generator()
+	if generator == nil {
+		return newError("yield statement can only be used inside generator function at %s", yieldStatement.Token.Location)
+	}
+	value := Eval(yieldStatement.Value, env)
+	if isError(value) {
+		return value
+	}
+	generator.Values <- value
+	if !<-generator.Resume {
+		return &object.ReturnValue{Value: NULL}
+	}
+	return nil
+}
+
+// Returns the generator iterated by a loop, if the loop created it. ie, the iterator is a call like count()
+// Such a generator must be closed when the loop exits early, or its paused goroutine is never released
+// A generator held in a variable is not returned, as it can be consumed further after the loop
+func loopGenerator(iterator ast.Expression, iterable object.Iterable) (*object.Generator, bool) {
+	generator, ok := iterable.(*object.Generator)
+	if !ok {
+		return nil, false
+	}
+	if _, isVariable := iterator.(*ast.Identifier); isVariable {
+		return nil, false
+	}
+	return generator, true
+}
+
+// Returns a function which returns the elements of the iterable one at a time
+// Generators are consumed lazily, so that infinite generators can be iterated
+func elementIterator(iterable object.Iterable) func() (object.Object, bool) {
+	if generator, ok := iterable.(*object.Generator); ok {
+		return generator.Next
+	}
+	elements := iterable.Iter().Elements
+	index := 0
+	return func() (object.Object, bool) {
+		if index >= len(elements) {
+			return nil, false
+		}
+		index++
+		return elements[index-1], true
+	}
+}
+
+// Returns the next value of the generator
+// Returns NULL if the generator has finished
+func next(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	generator, ok := arguments[0].(*object.Generator)
+	if !ok {
+		return newError("Argument to next must be GENERATOR. Got %s", arguments[0].Type())
+	}
+	value, ok := generator.Next()
+	if !ok {
+		return NULL
+	}
+	return value
+}
@@ -0,0 +1,59 @@
+package evaluator
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestGenerators(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let count = fn() { let n = 0; while true { yield n; n = n + 1 } };
+		  let taken = []
+		  for n in count() { if n == 3 { break }; taken = push(taken, n) }
+		  taken`, "[0, 1, 2]"},
+		{`let three = fn() { yield 1; yield 2; yield 3 };
+		  let numbers = three();
+		  [next(numbers), next(numbers)]`, "[1, 2]"},
+		{`let three = fn() { yield 1; yield 2; yield 3 };
+		  [n * 2 for n in three()]`, "[2, 4, 6]"},
+	})
+}
+
+// A loop which exits early leaves the rest of the generator for the next reader
+func TestGeneratorAfterBreak(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`let count = fn() { let n = 0; while true { yield n; n++ } };
+		  let numbers = count()
+		  for n in numbers { if n == 1 { break } }
+		  next(numbers)`, "2"},
+	})
+}
+
+// Loops which exit early from an infinite generator they created must not leave its goroutine blocked
+func TestGeneratorClosedOnEarlyExit(t *testing.T) {
+	inputs := []string{
+		`let count = fn() { let n = 0; while true { yield n; n++ } };
+		 for n in count() { if n == 2 { break } }`,
+		`let count = fn() { let n = 0; while true { yield n; n++ } };
+		 let first = fn() { for n in count() { return n } }
+		 first()`,
+		`let count = fn() { let n = 0; while true { yield n; n++ } };
+		 for n in count() { n + "a" }`,
+		`let count = fn() { let n = 0; while true { yield n; n++ } };
+		 [n + "a" for n in count()]`,
+	}
+	for _, input := range inputs {
+		before := runtime.NumGoroutine()
+		for i := 0; i < 20; i++ {
+			testEval(t, input)
+		}
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if leaked := runtime.NumGoroutine() - before; leaked > 0 {
+			t.Errorf("%q: %d generator goroutines were not released", input, leaked)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package evaluator
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Timeout for the requests made by http module, unless it was changed
+const defaultHTTPTimeout = 30 * time.Second
+
+// Timeout for the requests made by http module in nanoseconds. Default timeout is used if it is 0
+// It is atomic, as a spawned task can change it while the other tasks are making requests
+var httpTimeout atomic.Int64
+
+// Changes the timeout for the requests made by http module
+// Can be called by embedders, and from script using http.setTimeout(milliseconds)
+func SetHTTPTimeout(timeout time.Duration) {
+	httpTimeout.Store(int64(timeout))
+}
+
+// Returns the timeout for the requests made by http module
+func HTTPTimeout() time.Duration {
+	if timeout := httpTimeout.Load(); timeout != 0 {
+		return time.Duration(timeout)
+	}
+	return defaultHTTPTimeout
+}
+
+// Builtin http module
+// Example: import "http"; http.get("https://example.com").status
+var httpModule = &object.Module{
+	Name: "http",
+	Members: map[string]object.Object{
+		"get":        &object.Builtin{Fn: httpGet},
+		"post":       &object.Builtin{Fn: httpPost},
+		"setTimeout": &object.Builtin{Fn: httpSetTimeout},
+	},
+}
+
+// Sends a GET request to the url and returns the response as hash
+func httpGet(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ {
+		return newError("Url to get must be STRING. Got %s", arguments[0].Type())
+	}
+	client := &http.Client{Timeout: HTTPTimeout()}
+	response, err := client.Get(arguments[0].(*object.String).Value)
+	if err != nil {
+		return newError("HTTP request failed: %s", err)
+	}
+	return responseToHash(response)
+}
+
+// Sends a POST request with body to the url and returns the response as hash
+// Content type will be text/plain, if not supplied
+func httpPost(arguments ...object.Object) object.Object {
+	if 2 > len(arguments) || len(arguments) > 3 {
+		return newError("Wrong number of arguments. Got=%d want=(min:2, max: 3)", len(arguments))
+	}
+	for _, argument := range arguments {
+		if argument.Type() != object.STRING_OBJ {
+			return newError("Arguments to post must be STRING. Got %s", argument.Type())
+		}
+	}
+	contentType := "text/plain"
+	if len(arguments) == 3 {
+		contentType = arguments[2].(*object.String).Value
+	}
+	client := &http.Client{Timeout: HTTPTimeout()}
+	body := strings.NewReader(arguments[1].(*object.String).Value)
+	response, err := client.Post(arguments[0].(*object.String).Value, contentType, body)
+	if err != nil {
+		return newError("HTTP request failed: %s", err)
+	}
+	return responseToHash(response)
+}
+
+// Sets the timeout of http requests in milliseconds
+func httpSetTimeout(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	if arguments[0].Type() != object.INTEGER_OBJ {
+		return newError("Timeout must be INTEGER. Got %s", arguments[0].Type())
+	}
+	milliseconds := arguments[0].(*object.Integer).Value
+	if milliseconds <= 0 {
+		return newError("Timeout must be positive. Got %d", milliseconds)
+	}
+	SetHTTPTimeout(time.Duration(milliseconds) * time.Millisecond)
+	return nil
+}
+
+// Reads the response and converts it to a hash of status, body and headers
+func responseToHash(response *http.Response) object.Object {
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return newError("Cannot read HTTP response: %s", err)
+	}
+	headers := make(map[string]object.Object)
+	for name, values := range response.Header {
+		headers[name] = &object.String{Value: strings.Join(values, ", ")}
+	}
+	return newStringKeyHash(map[string]object.Object{
+		"status":  &object.Integer{Value: response.StatusCode},
+		"body":    &object.String{Value: string(body)},
+		"headers": newStringKeyHash(headers),
+	})
+}
@@ -0,0 +1,57 @@
+package evaluator
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHttpModule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		writer.Header().Set("X-Method", request.Method)
+		writer.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(writer, "%s %s", request.Method, body)
+	}))
+	runEvalTests(t, []struct{ input, expected string }{
+		{`import "http"; let response = http.get("` + server.URL + `"); [response["status"], response["body"]]`, "[201, GET ]"},
+		{`import "http"; http.get("` + server.URL + `")["headers"]["X-Method"]`, "GET"},
+		{`import "http"; http.post("` + server.URL + `", "hi")["body"]`, "POST hi"},
+	})
+	server.Close()
+
+	result := inspect(testEval(t, `import "http"; http.get("`+server.URL+`")`))
+	if !strings.HasPrefix(result, "EVAL ERROR: HTTP request failed: ") {
+		t.Errorf("Expected network error, got %s", result)
+	}
+}
+
+// Timeout set by a task applies to the requests of the other tasks. Run with -race
+func TestHttpSetTimeout(t *testing.T) {
+	defer SetHTTPTimeout(0)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/slow" {
+			time.Sleep(500 * time.Millisecond)
+		}
+		fmt.Fprint(writer, "done")
+	}))
+	defer server.Close()
+
+	runEvalTests(t, []struct{ input, expected string }{
+		{`import "http"; http.setTimeout(0)`, "EVAL ERROR: Timeout must be positive. Got 0"},
+		{`import "http"; http.setTimeout("1")`, "EVAL ERROR: Timeout must be INTEGER. Got STRING"},
+		{`import "http"; let tasks = [spawn(fn() { http.setTimeout(2000); http.get("` + server.URL + `")["body"] }) for i in range(0, 4)]; map(tasks, wait)`, "[done, done, done, done]"},
+	})
+	if timeout := HTTPTimeout(); timeout != 2*time.Second {
+		t.Errorf("Expected timeout of 2s, got %s", timeout)
+	}
+
+	result := inspect(testEval(t, `import "http"; http.setTimeout(50); http.get("`+server.URL+`/slow")`))
+	if !strings.HasPrefix(result, "EVAL ERROR: HTTP request failed: ") || !strings.Contains(result, "Timeout") {
+		t.Errorf("Expected timeout error, got %s", result)
+	}
+}
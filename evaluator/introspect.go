@@ -0,0 +1,50 @@
+package evaluator
+
+import "github.com/mochatek/frolang/object"
+
+// Builtins which need the environment they are called from, like globals and locals
+// They are registered with the other builtins, and bound to the environment when the identifier is evaluated
+type scopeFunction func(env *object.Environment, arguments ...object.Object) object.Object
+
+func (function scopeFunction) Type() object.ObjectType { return object.BUILTIN_OBJ }
+func (function scopeFunction) Inspect() string         { return "Builtin function" }
+
+// Returns the builtin which calls the scope function with the supplied environment
+func bindScope(function scopeFunction, env *object.Environment) *object.Builtin {
+	return &object.Builtin{Fn: func(arguments ...object.Object) object.Object {
+		return function(env, arguments...)
+	}}
+}
+
+// Returns a hash of the variables declared in the global (outermost) environment
+func globals(env *object.Environment, arguments ...object.Object) object.Object {
+	if len(arguments) != 0 {
+		return newError("Wrong number of arguments. Got=%d want=0", len(arguments))
+	}
+	global := env
+	for global.Outer() != nil {
+		global = global.Outer()
+	}
+	return newStringKeyHash(global.Bindings())
+}
+
+// Returns a hash of the variables visible in the current scope, excluding the global variables
+// Variables of inner scopes shadow the ones with same name from outer scopes
+// At top level, the global variables are the local variables
+func locals(env *object.Environment, arguments ...object.Object) object.Object {
+	if len(arguments) != 0 {
+		return newError("Wrong number of arguments. Got=%d want=0", len(arguments))
+	}
+	if env.Outer() == nil {
+		return newStringKeyHash(env.Bindings())
+	}
+	bindings := make(map[string]object.Object)
+	for scope := env; scope.Outer() != nil; scope = scope.Outer() {
+		for name, value := range scope.Bindings() {
+			if _, ok := bindings[name]; !ok {
+				bindings[name] = value
+			}
+		}
+	}
+	return newStringKeyHash(bindings)
+}
@@ -0,0 +1,172 @@
+package evaluator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Builtin json module
+// Example: import "json"; json.stringify({"a": [1, 2]}, 2)
+var jsonModule = &object.Module{
+	Name: "json",
+	Members: map[string]object.Object{
+		"stringify": &object.Builtin{Fn: jsonStringify},
+		"parse":     &object.Builtin{Fn: jsonParse},
+	},
+}
+
+// Returns the JSON string of a value
+// If indent (number of spaces or an indent string) is supplied, then output will be pretty printed
+// Keys of a hash are sorted in the output
+func jsonStringify(arguments ...object.Object) object.Object {
+	if 1 > len(arguments) || len(arguments) > 2 {
+		return newError("Wrong number of arguments. Got=%d want=(min:1, max: 2)", len(arguments))
+	}
+	indent := ""
+	if len(arguments) == 2 {
+		switch arg := arguments[1].(type) {
+		case *object.Integer:
+			if arg.Value < 0 {
+				return newError("Indent to stringify must be non-negative. Got %d", arg.Value)
+			}
+			indent = strings.Repeat(" ", arg.Value)
+		case *object.String:
+			indent = arg.Value
+		default:
+			return newError("Indent to stringify must be INTEGER/STRING. Got %s", arguments[1].Type())
+		}
+	}
+	value, err := objectToJSON(arguments[0])
+	if err != nil {
+		return err
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(value); err != nil {
+		return newError("Cannot stringify to JSON: %s", err)
+	}
+	return &object.String{Value: strings.TrimSuffix(buffer.String(), "\n")}
+}
+
+// Returns the value represented by a JSON string
+func jsonParse(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ {
+		return newError("Argument to parse must be STRING. Got %s", arguments[0].Type())
+	}
+	decoder := json.NewDecoder(strings.NewReader(arguments[0].(*object.String).Value))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return newError("Invalid JSON: %s", err)
+	}
+	if decoder.More() {
+		return newError("Invalid JSON: unexpected data after top-level value")
+	}
+	return jsonToObject(value)
+}
+
+// Converts an object to a value that can be encoded to JSON
+// Hash keys must be strings or numbers. Numbers are converted to string with full precision
+// Return error if two keys of a hash convert to the same string (Eg: 1 and "1")
+// Return error for objects which are not serializable (Eg: functions)
+func objectToJSON(obj object.Object) (interface{}, *object.Error) {
+	switch obj := obj.(type) {
+	case *object.Null:
+		return nil, nil
+	case *object.Integer:
+		return obj.Value, nil
+	case *object.Float:
+		return obj.Value, nil
+	case *object.Boolean:
+		return obj.Value, nil
+	case *object.String:
+		return obj.Value, nil
+	case *object.Array:
+		values := make([]interface{}, len(obj.Elements), len(obj.Elements))
+		for idx, element := range obj.Elements {
+			value, err := objectToJSON(element)
+			if err != nil {
+				return nil, err
+			}
+			values[idx] = value
+		}
+		return values, nil
+	case *object.Hash:
+		values := make(map[string]interface{}, len(obj.Pairs))
+		for _, pair := range obj.Pairs {
+			value, err := objectToJSON(pair.Value)
+			if err != nil {
+				return nil, err
+			}
+			key, err := jsonKey(pair.Key)
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := values[key]; exists {
+				return nil, newError("Duplicate key in JSON object: %s", key)
+			}
+			values[key] = value
+		}
+		return values, nil
+	default:
+		return nil, newError("Value of type %s is not JSON serializable", obj.Type())
+	}
+}
+
+// Returns the string used as JSON object key for a hash key
+func jsonKey(key object.Object) (string, *object.Error) {
+	switch key := key.(type) {
+	case *object.String:
+		return key.Value, nil
+	case *object.Integer:
+		return strconv.Itoa(key.Value), nil
+	case *object.Float:
+		return strconv.FormatFloat(key.Value, 'g', -1, 64), nil
+	default:
+		return "", newError("Key of type %s is not JSON serializable", key.Type())
+	}
+}
+
+// Converts a decoded JSON value to object
+// Numbers without fraction or exponent become integers. Others become floats
+func jsonToObject(value interface{}) object.Object {
+	switch value := value.(type) {
+	case nil:
+		return NULL
+	case bool:
+		return nativeToBooleanObject(value)
+	case string:
+		return &object.String{Value: value}
+	case json.Number:
+		if integer, err := value.Int64(); err == nil {
+			return &object.Integer{Value: int(integer)}
+		}
+		float, err := value.Float64()
+		if err != nil {
+			return newError("Invalid JSON number: %s", value)
+		}
+		return &object.Float{Value: float}
+	case []interface{}:
+		elements := make([]object.Object, len(value), len(value))
+		for idx, element := range value {
+			elements[idx] = jsonToObject(element)
+		}
+		return &object.Array{Elements: elements}
+	case map[string]interface{}:
+		values := make(map[string]object.Object, len(value))
+		for key, element := range value {
+			values[key] = jsonToObject(element)
+		}
+		return newStringKeyHash(values)
+	}
+	return NULL
+}
@@ -0,0 +1,29 @@
+package evaluator
+
+import "testing"
+
+func TestJsonPretty(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`import "json"; json.stringify({"a": [1, {"b": true}]}, 2)`, "{\n  \"a\": [\n    1,\n    {\n      \"b\": true\n    }\n  ]\n}"},
+		{`import "json"; json.stringify([1, [2]])`, "[1,[2]]"},
+	})
+}
+
+func TestJsonParse(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`import "json"; json.parse("[1, 2.5, null]")[0] + 1`, "2"},
+		{`import "json"; json.parse(json.stringify({"a": "b"}))["a"]`, "b"},
+		{`import "json"; json.parse("[1] 2")`, "EVAL ERROR: Invalid JSON: unexpected data after top-level value"},
+	})
+}
+
+func TestJsonStringify(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`import "json"; json.stringify({"a": [1, 2.5, true, print()]})`, `{"a":[1,2.5,true,null]}`},
+		{`import "json"; json.stringify({1: "a", 2: "b"})`, `{"1":"a","2":"b"}`},
+		{`import "json"; json.stringify({0.1: "a", 1.123456789: "b"})`, `{"0.1":"a","1.123456789":"b"}`},
+		{`import "json"; json.stringify({1: "a", "1": "b"})`, "EVAL ERROR: Duplicate key in JSON object: 1"},
+		{`import "json"; json.stringify({true: "a"})`, "EVAL ERROR: Key of type BOOLEAN is not JSON serializable"},
+		{`import "json"; json.stringify({"f": fn() {}})`, "EVAL ERROR: Value of type FUNCTION is not JSON serializable"},
+	})
+}
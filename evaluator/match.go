@@ -0,0 +1,141 @@
+package evaluator
+
+import (
+	"github.com/mochatek/frolang/ast"
+	"github.com/mochatek/frolang/object"
+	"github.com/mochatek/frolang/token"
+)
+
+// Name of the pattern which matches any value without binding it
+const wildcard = "_"
+
+// Evaluates a match expression
+// Evaluate the subject and try the patterns of the arms in order
+// For the first arm whose pattern matches and guard (if any) is truthy,
+// evaluate its body with the variables bound by the pattern
+// Return the result of that body. If no pattern matched, then return NULL
+func evalMatchExpression(matchExpression *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(matchExpression.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+	for _, arm := range matchExpression.Arms {
+		bindings := make(map[string]object.Object)
+		matched, err := matchPattern(arm.Pattern, subject, bindings, env)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+		armEnv := object.NewEnclosedEnvironment(env)
+		for name, value := range bindings {
+			armEnv.Set(name, value)
+		}
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTrue(guard) {
+				continue
+			}
+		}
+		return Eval(arm.Body, armEnv)
+	}
+	return NULL
+}
+
+// Checks whether the value matches the pattern, collecting the variables bound by the pattern
+// _ matches any value and an identifier matches any value by binding it
+// Array pattern matches an array of same length whose elements match the element patterns
+// Its last element can be ...IDENTIFIER, which binds the remaining elements as an array
+// Hash pattern matches a hash which has all the keys of the pattern, with values matching the value patterns
+// Any other expression is evaluated and matches a value equal to it
+func matchPattern(pattern ast.Expression, value object.Object, bindings map[string]object.Object, env *object.Environment) (bool, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value != wildcard {
+			bindings[pattern.Value] = value
+		}
+		return true, nil
+	case *ast.ArrayLiteral:
+		array, ok := value.(*object.Array)
+		if !ok {
+			return false, nil
+		}
+		return matchArrayPattern(pattern, array, bindings, env)
+	case *ast.HashLiteral:
+		hash, ok := value.(*object.Hash)
+		if !ok {
+			return false, nil
+		}
+		return matchHashPattern(pattern, hash, bindings, env)
+	}
+	expected := Eval(pattern, env)
+	if isError(expected) {
+		return false, expected.(*object.Error)
+	}
+	equal := evalInfixOperation(expected, token.EQ, value)
+	if isError(equal) {
+		return false, equal.(*object.Error)
+	}
+	return isTrue(equal), nil
+}
+
+// Matches the elements of an array against the element patterns
+func matchArrayPattern(pattern *ast.ArrayLiteral, array *object.Array, bindings map[string]object.Object, env *object.Environment) (bool, *object.Error) {
+	elements := pattern.Elements
+	var rest *ast.Identifier
+	if count := len(elements); count > 0 {
+		if spread, ok := elements[count-1].(*ast.SpreadExpression); ok {
+			identifier, ok := spread.Value.(*ast.Identifier)
+			if !ok {
+				return false, newError("Rest of array pattern must be an identifier at %s", spread.Token.Location)
+			}
+			rest = identifier
+			elements = elements[:count-1]
+		}
+	}
+	if len(array.Elements) < len(elements) || (rest == nil && len(array.Elements) != len(elements)) {
+		return false, nil
+	}
+	for index, element := range elements {
+		if _, ok := element.(*ast.SpreadExpression); ok {
+			return false, newError("Rest of array pattern must be its last element at %s", pattern.Token.Location)
+		}
+		matched, err := matchPattern(element, array.Elements[index], bindings, env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	if rest != nil && rest.Value != wildcard {
+		remaining := make([]object.Object, len(array.Elements)-len(elements))
+		copy(remaining, array.Elements[len(elements):])
+		bindings[rest.Value] = &object.Array{Elements: remaining}
+	}
+	return true, nil
+}
+
+// Matches the values of a hash against the value patterns of the keys in the pattern
+func matchHashPattern(pattern *ast.HashLiteral, hash *object.Hash, bindings map[string]object.Object, env *object.Environment) (bool, *object.Error) {
+	for keyNode, valuePattern := range pattern.Pairs {
+		key := Eval(keyNode, env)
+		if isError(key) {
+			return false, key.(*object.Error)
+		}
+		hashKey, ok := object.HashKeyOf(key)
+		if !ok {
+			return false, newError("%s: is not hashable", key.Type())
+		}
+		pair, ok := hash.Pairs[hashKey]
+		if !ok {
+			return false, nil
+		}
+		matched, err := matchPattern(valuePattern, pair.Value, bindings, env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
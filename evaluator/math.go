@@ -0,0 +1,110 @@
+package evaluator
+
+import (
+	"math"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Builtin math module
+// Example: import "math"; math.sqrt(2)
+var mathModule = &object.Module{
+	Name: "math",
+	Members: map[string]object.Object{
+		"pi":    &object.Float{Value: math.Pi},
+		"e":     &object.Float{Value: math.E},
+		"sqrt":  mathFunction("sqrt", math.Sqrt),
+		"sin":   mathFunction("sin", math.Sin),
+		"cos":   mathFunction("cos", math.Cos),
+		"tan":   mathFunction("tan", math.Tan),
+		"log":   mathFunction("log", math.Log),
+		"exp":   mathFunction("exp", math.Exp),
+		"floor": mathRounding("floor", math.Floor),
+		"ceil":  mathRounding("ceil", math.Ceil),
+		"round": mathRounding("round", math.Round),
+		"abs":   &object.Builtin{Fn: mathAbs},
+		"pow":   &object.Builtin{Fn: mathPow},
+	},
+}
+
+// Helper function to convert number object to float
+func toFloat(obj object.Object) (float64, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value), true
+	case *object.Float:
+		return obj.Value, true
+	}
+	return 0, false
+}
+
+// Creates a builtin that applies a float function on a number and returns the float result
+// Return error if the result is not a number (Domain error)
+func mathFunction(name string, function func(float64) float64) *object.Builtin {
+	return &object.Builtin{Fn: func(arguments ...object.Object) object.Object {
+		if len(arguments) != 1 {
+			return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+		}
+		value, ok := toFloat(arguments[0])
+		if !ok {
+			return newError("Argument to %s must be INTEGER/FLOAT. Got %s", name, arguments[0].Type())
+		}
+		result := function(value)
+		if math.IsNaN(result) {
+			return newError("Math domain error: %s(%s)", name, arguments[0].Inspect())
+		}
+		return &object.Float{Value: result}
+	}}
+}
+
+// Creates a builtin that rounds a number using the rounding function and returns the integer result
+func mathRounding(name string, function func(float64) float64) *object.Builtin {
+	return &object.Builtin{Fn: func(arguments ...object.Object) object.Object {
+		if len(arguments) != 1 {
+			return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+		}
+		value, ok := toFloat(arguments[0])
+		if !ok {
+			return newError("Argument to %s must be INTEGER/FLOAT. Got %s", name, arguments[0].Type())
+		}
+		return &object.Integer{Value: int(function(value))}
+	}}
+}
+
+// Returns the absolute value of a number
+func mathAbs(arguments ...object.Object) object.Object {
+	if len(arguments) != 1 {
+		return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+	}
+	switch arg := arguments[0].(type) {
+	case *object.Integer:
+		if arg.Value < 0 {
+			return &object.Integer{Value: -arg.Value}
+		}
+		return arg
+	case *object.Float:
+		return &object.Float{Value: math.Abs(arg.Value)}
+	default:
+		return newError("Argument to abs must be INTEGER/FLOAT. Got %s", arguments[0].Type())
+	}
+}
+
+// Returns base raised to the power of exponent
+func mathPow(arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	base, ok := toFloat(arguments[0])
+	if !ok {
+		return newError("Base to pow must be INTEGER/FLOAT. Got %s", arguments[0].Type())
+	}
+	exponent, ok := toFloat(arguments[1])
+	if !ok {
+		return newError("Exponent to pow must be INTEGER/FLOAT. Got %s", arguments[1].Type())
+	}
+	result := math.Pow(base, exponent)
+	if math.IsNaN(result) {
+		return newError("Math domain error: pow(%s, %s)", arguments[0].Inspect(), arguments[1].Inspect())
+	}
+	return &object.Float{Value: result}
+}
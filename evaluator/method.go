@@ -0,0 +1,53 @@
+package evaluator
+
+import (
+	"github.com/mochatek/frolang/object"
+)
+
+// Names of the builtins which can be called as methods of an array
+// Example: [1, 2, 3].map(fn(x) { x * 2 }).filter(fn(x) { x > 2 })
+var arrayMethods = map[string]bool{
+	"map": true, "filter": true, "reduce": true, "flatMap": true, "scan": true,
+	"takeWhile": true, "dropWhile": true, "minBy": true, "maxBy": true, "min": true, "max": true,
+	"sort": true, "sorted": true, "join": true, "len": true, "reversed": true, "slice": true,
+	"take": true, "drop": true, "chunk": true, "windows": true, "push": true, "pop": true,
+	"unshift": true, "shift": true, "swap": true, "resize": true,
+}
+
+// Names of the builtins which can be called as methods of a string
+// Members of the strings module are looked up first, so that module-only ones like trim are available too
+// Example: " Hello ".trim().upper().split("")
+var stringMethods = map[string]bool{
+	"upper": true, "lower": true, "split": true, "trim": true, "strip": true, "replace": true,
+	"contains": true, "repeat": true, "padStart": true, "padEnd": true, "startsWith": true,
+	"endsWith": true, "lines": true, "words": true, "len": true, "reversed": true, "slice": true,
+}
+
+// Returns the builtin with the name as a method bound to the array
+// Calling the method calls the builtin with the array as its first argument
+func arrayMethod(array *object.Array, name string) (object.Object, bool) {
+	if !arrayMethods[name] {
+		return nil, false
+	}
+	return boundBuiltin(builtins[name].(*object.Builtin), array), true
+}
+
+// Returns the builtin with the name as a method bound to the string
+// Calling the method calls the builtin with the string as its first argument
+func stringMethod(str *object.String, name string) (object.Object, bool) {
+	if !stringMethods[name] {
+		return nil, false
+	}
+	member, ok := stringsModule.Members[name]
+	if !ok {
+		member = builtins[name]
+	}
+	return boundBuiltin(member.(*object.Builtin), str), true
+}
+
+// Returns a builtin which calls the builtin with the receiver followed by its arguments
+func boundBuiltin(builtin *object.Builtin, receiver object.Object) *object.Builtin {
+	return &object.Builtin{Fn: func(arguments ...object.Object) object.Object {
+		return builtin.Fn(append([]object.Object{receiver}, arguments...)...)
+	}}
+}
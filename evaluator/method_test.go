@@ -0,0 +1,18 @@
+package evaluator
+
+import "testing"
+
+func TestArrayMethods(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`[1, 2, 3].map(fn(x) { x * 2 }).filter(fn(x) { x > 2 })`, "[4, 6]"},
+		{`let items = [1, 2, 3]; items.reduce(fn(sum, x) { sum + x }, 0)`, "6"},
+		{`[1].nope()`, "EVAL ERROR: Array has no method nope at 1:5"},
+	})
+}
+
+func TestStringMethods(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`"ab".upper().split().join("-")`, "A-B"},
+		{`"FroLang".lower().startsWith("fro")`, "true"},
+	})
+}
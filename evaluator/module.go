@@ -0,0 +1,134 @@
+package evaluator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mochatek/frolang/ast"
+	"github.com/mochatek/frolang/lexer"
+	"github.com/mochatek/frolang/object"
+	"github.com/mochatek/frolang/parser"
+)
+
+// Builtin modules which are imported by their name instead of a file path
+var modules = map[string]*object.Module{
+	"math":    mathModule,
+	"strings": stringsModule,
+	"fs":      fsModule,
+	"http":    httpModule,
+	"json":    jsonModule,
+	"csv":     csvModule,
+	"time":    timeModule,
+	"encode":  encodeModule,
+	"decode":  decodeModule,
+}
+
+// Returns the name to which a builtin module is bound when imported without alias
+// Returns false if the path doesn't refer to a builtin module
+func BuiltinModuleName(path string) (string, bool) {
+	module, ok := modules[path]
+	if !ok {
+		return "", false
+	}
+	return module.Name, true
+}
+
+// Resolves the import path relative to the directory of the importing file
+// If the importing code does not come from a file (REPL), path is resolved relative to working directory
+func resolveImportPath(path string, env *object.Environment) (string, error) {
+	if !filepath.IsAbs(path) {
+		if importer := env.Path(); importer != "" {
+			path = filepath.Join(filepath.Dir(importer), path)
+		}
+	}
+	return filepath.Abs(path)
+}
+
+// Resolve the path of imported file
+// Return error if that file is already being imported or is the entry script (Circular import)
+// Chain of the modules being imported is carried by the environment, so that concurrent imports don't share it
+// Read, parse and evaluate the source code in a fresh environment, which has the options of the importing run
+// Return error if any of that failed
+// Otherwise, return the exported variables of the evaluated module
+func loadModule(path string, env *object.Environment) (map[string]object.Object, *object.Error) {
+	modulePath, err := resolveImportPath(path, env)
+	if err != nil {
+		return nil, newError("Cannot import %s: %s", path, err)
+	}
+	importing := env.Imports()
+	if len(importing) == 0 && env.Path() != "" {
+		if entryPath, err := filepath.Abs(env.Path()); err == nil {
+			importing = []string{entryPath}
+		}
+	}
+	for index, importingPath := range importing {
+		if importingPath == modulePath {
+			cycle := append(append([]string{}, importing[index:]...), modulePath)
+			return nil, newError("Circular import: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	contentBytes, err := os.ReadFile(modulePath)
+	if err != nil {
+		return nil, newError("Cannot import %s: %s", path, err)
+	}
+	par := parser.New(lexer.New(string(contentBytes)))
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		return nil, newError("Cannot import %s: %s", path, strings.Join(par.Errors(), ", "))
+	}
+
+	moduleEnv := object.NewEnvironment()
+	moduleEnv.SetPath(modulePath)
+	moduleEnv.SetImports(append(append([]string{}, importing...), modulePath))
+	moduleEnv.SetOptions(env.Options())
+	if result := Eval(program, moduleEnv); isError(result) {
+		return nil, result.(*object.Error)
+	}
+	return moduleExports(program, moduleEnv), nil
+}
+
+// Collect the variables declared by top level export statements of the module
+// Variables which are not exported stay private to the module
+func moduleExports(program *ast.Program, moduleEnv *object.Environment) map[string]object.Object {
+	exports := make(map[string]object.Object)
+	for _, statement := range program.Statements {
+		if exportStatement, ok := statement.(*ast.ExportStatement); ok {
+			name := exportStatement.Statement.Name.Value
+			if value, ok := moduleEnv.Get(name); ok {
+				exports[name] = value
+			}
+		}
+	}
+	return exports
+}
+
+// If path is the name of a builtin module, then bind that module to the alias or to its own name
+// Otherwise, load the imported module
+// If loading failed, then return the error
+// If alias was supplied, then bind a module object holding the exported variables of the module to the alias
+// Otherwise, bind every exported variable of the module into the importing environment
+func evalImportStatement(importStatement *ast.ImportStatement, env *object.Environment) object.Object {
+	if module, ok := modules[importStatement.Path.Value]; ok {
+		name := module.Name
+		if importStatement.Alias != nil {
+			name = importStatement.Alias.Value
+		}
+		env.Set(name, module)
+		return nil
+	}
+	exports, err := loadModule(importStatement.Path.Value, env)
+	if err != nil {
+		return err
+	}
+	if importStatement.Alias != nil {
+		module := &object.Module{Name: importStatement.Alias.Value, Members: exports}
+		env.Set(importStatement.Alias.Value, module)
+		return nil
+	}
+	for name, value := range exports {
+		env.Set(name, value)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package evaluator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mochatek/frolang/lexer"
+	"github.com/mochatek/frolang/object"
+	"github.com/mochatek/frolang/parser"
+)
+
+// Module used by the import tests, exporting its function
+const libSource = `export let double = fn(x) { x * 2 }`
+
+// Writes the files into a temporary directory, and evaluates the entry script among them
+func evalScript(t *testing.T, files map[string]string, entry string) object.Object {
+	t.Helper()
+	directory := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(directory, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	par := parser.New(lexer.New(files[entry]))
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		t.Fatalf("Parse errors for %s: %v", entry, par.Errors())
+	}
+	env := object.NewEnvironment()
+	env.SetPath(filepath.Join(directory, entry))
+	return Eval(program, env)
+}
+
+func TestImports(t *testing.T) {
+	result := evalScript(t, map[string]string{
+		"main.fro": `import "lib.fro"; double(21)`,
+		"lib.fro":  libSource,
+	}, "main.fro")
+	if inspect(result) != "42" {
+		t.Errorf("Expected 42, got %s", inspect(result))
+	}
+
+	result = evalScript(t, map[string]string{
+		"main.fro": `import "a.fro"`,
+		"a.fro":    `import "b.fro"`,
+		"b.fro":    `import "a.fro"`,
+	}, "main.fro")
+	if message := inspect(result); !strings.Contains(message, "Circular import") || !strings.Contains(message, "b.fro -> ") {
+		t.Errorf("Expected circular import error, got %s", message)
+	}
+}
+
+// Tasks importing modules at the same time must not share the chain of imports. Run with -race
+func TestConcurrentImports(t *testing.T) {
+	result := evalScript(t, map[string]string{
+		"main.fro": `let tasks = [spawn(fn() { import "modx.fro"; x }) for i in range(0, 20)];
+		             reduce(map(tasks, wait), fn(sum, x) { sum + x }, 0)`,
+		"modx.fro": `import "mody.fro"; export let x = y`,
+		"mody.fro": `export let y = 1`,
+	}, "main.fro")
+	if inspect(result) != "20" {
+		t.Errorf("Expected 20, got %s", inspect(result))
+	}
+}
+
+func TestModuleNamespace(t *testing.T) {
+	files := map[string]string{
+		"lib.fro": libSource,
+	}
+	tests := []struct{ main, expected string }{
+		{`import "lib.fro" as lib; lib.double(21)`, "42"},
+		{`import "lib.fro" as lib; let double = 1; [double, lib.double(1)]`, "[1, 2]"},
+		{`import "lib.fro" as lib; double`, "EVAL ERROR: Identifier: double not found at 1:26"},
+	}
+	for _, test := range tests {
+		files["main.fro"] = test.main
+		if result := inspect(evalScript(t, files, "main.fro")); result != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.main, test.expected, result)
+		}
+	}
+}
+
+func TestModuleVisibility(t *testing.T) {
+	files := map[string]string{
+		"shapes.fro": `let secret = 2; let double = fn(x) { x * secret }; export let area = fn(w, h) { double(w * h) / 2 }`,
+	}
+	tests := []struct{ main, expected string }{
+		{`import "shapes.fro" as shapes; shapes.area(2, 3)`, "6"},
+		{`import "shapes.fro"; area(2, 3)`, "6"},
+		{`import "shapes.fro" as shapes; shapes.double`, "EVAL ERROR: Module: shapes has no member double at 1:39"},
+		{`import "shapes.fro"; secret`, "EVAL ERROR: Identifier: secret not found at 1:22"},
+	}
+	for _, test := range tests {
+		files["main.fro"] = test.main
+		if result := inspect(evalScript(t, files, "main.fro")); result != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.main, test.expected, result)
+		}
+	}
+}
+
+func TestMathModule(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`import "math"; [math.floor(math.pi * 100), math.sqrt(16), math.abs(-2), math.pow(2, 10)]`, "[314, 4.00, 2, 1024.00]"},
+		{`import "math" as m; [m.e > 2.71, m.e < 2.72]`, "[true, true]"},
+		{`import "math"; math.sqrt("a")`, "EVAL ERROR: Argument to sqrt must be INTEGER/FLOAT. Got STRING"},
+	})
+}
+
+func TestStringsModule(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`import "strings"; [strings.upper("ab"), strings.join(["a", "b"], "-"), strings.replace("aXa", "X", "b")]`, "[AB, a-b, aba]"},
+		{`import "strings"; [strings.contains("frolang", "lang"), strings.repeat("ab", 2), strings.trim("  hi  ")]`, "[true, abab, hi]"},
+	})
+}
+
+func TestFsModule(t *testing.T) {
+	directory := t.TempDir()
+	for _, name := range []string{"b.txt", "a.txt"} {
+		if err := os.WriteFile(filepath.Join(directory, name), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runEvalTests(t, []struct{ input, expected string }{
+		{`import "fs"; fs.listDir("` + directory + `")`, "[a.txt, b.txt]"},
+		{`import "fs"; [fs.exists("` + filepath.Join(directory, "a.txt") + `"), fs.exists("` + filepath.Join(directory, "missing") + `")]`, "[true, false]"},
+		{`import "fs"; [fs.isDir("` + directory + `"), fs.isDir("` + filepath.Join(directory, "a.txt") + `")]`, "[true, false]"},
+	})
+}
+
+func TestTimeModule(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`import "time"; time.format(0, "2006-01-02 15:04:05")`, "1970-01-01 00:00:00"},
+		{`import "time"; time.format(time.parse("2024-02-29 13:45", "2006-01-02 15:04"), "2006-01-02 15:04")`, "2024-02-29 13:45"},
+		{`import "time"; time.now() > 0`, "true"},
+	})
+}
+
+func TestEncodingModules(t *testing.T) {
+	runEvalTests(t, []struct{ input, expected string }{
+		{`import "encode"; [encode.base64("FroLang"), encode.hex("Fro")]`, "[RnJvTGFuZw==, 46726f]"},
+		{`import "encode"; import "decode"; [decode.base64(encode.base64("FroLang")), decode.hex(encode.hex("hé"))]`, "[FroLang, hé]"},
+	})
+}
@@ -0,0 +1,32 @@
+package evaluator
+
+import (
+	"io"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Settings of an evaluation run, which are set on the environment of the program
+// Each run has its own options, so that programs evaluated at the same time don't affect each other
+// Functions, spawned tasks and imported modules of the program use the options of the program
+// Example: env.SetOptions(&evaluator.Options{MaxIterations: 1000}); evaluator.Eval(program, env)
+type Options struct {
+	// Maximum number of iterations a single loop is allowed to run. Loops are unlimited when it is 0
+	MaxIterations int
+	// Writer to which each node is logged before it is evaluated, to follow the order of evaluation. ie, os.Stderr
+	// Tracing is disabled if it is nil
+	Trace io.Writer
+	// Debugger notified around each statement of the run. It is not used if nil
+	Debugger Debugger
+}
+
+// Options of the runs whose environment has no options
+var defaultOptions = &Options{}
+
+// Returns the options of the run evaluating code in the environment
+func optionsOf(env *object.Environment) *Options {
+	if options, ok := env.Options().(*Options); ok {
+		return options
+	}
+	return defaultOptions
+}
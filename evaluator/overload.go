@@ -0,0 +1,60 @@
+package evaluator
+
+import (
+	"github.com/mochatek/frolang/object"
+	"github.com/mochatek/frolang/token"
+)
+
+// Names of the hash keys, using which a hash can overload the operators
+var operatorMethods = map[string]string{
+	token.PLUS:     "__add__",
+	token.MINUS:    "__sub__",
+	token.ASTERISK: "__mul__",
+	token.SLASH:    "__div__",
+	token.EQ:       "__eq__",
+	token.NOT_EQ:   "__ne__",
+	token.LT:       "__lt__",
+	token.LT_EQ:    "__le__",
+	token.GT:       "__gt__",
+	token.GT_EQ:    "__ge__",
+}
+
+// If the left operand is a hash with a callable value for the method name of the operator,
+// then call it with both operands (left operand being the first argument) and return the result
+// If != is not overloaded but == is, then return the negated result of __eq__
+// Returns false if the operation is not overloaded
+func evalOverloadedOperation(leftOperand object.Object, operator string, rightOperand object.Object) (object.Object, bool) {
+	hash, ok := leftOperand.(*object.Hash)
+	if !ok {
+		return nil, false
+	}
+	if method, ok := hashMethod(hash, operatorMethods[operator]); ok {
+		return applyFunction(method, []object.Object{leftOperand, rightOperand}), true
+	}
+	if operator == token.NOT_EQ {
+		if method, ok := hashMethod(hash, operatorMethods[token.EQ]); ok {
+			result := applyFunction(method, []object.Object{leftOperand, rightOperand})
+			if isError(result) {
+				return result, true
+			}
+			return nativeToBooleanObject(!isTrue(result)), true
+		}
+	}
+	return nil, false
+}
+
+// Returns the value of the key in the hash, if it is callable
+func hashMethod(hash *object.Hash, name string) (object.Object, bool) {
+	if name == "" {
+		return nil, false
+	}
+	pair, ok := hash.Pairs[(&object.String{Value: name}).HashKey()]
+	if !ok {
+		return nil, false
+	}
+	switch pair.Value.(type) {
+	case *object.Function, *object.Builtin:
+		return pair.Value, true
+	}
+	return nil, false
+}
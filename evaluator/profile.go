@@ -0,0 +1,88 @@
+package evaluator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mochatek/frolang/ast"
+	"github.com/mochatek/frolang/object"
+)
+
+// Number of calls of a user function and the total time spent in them
+// Time of recursive calls is included in the time of their outermost call
+type FunctionProfile struct {
+	Name  string
+	Calls int
+	Time  time.Duration
+	depth int
+}
+
+// Debugger which tallies the calls of each user function and the time spent in them
+// Example: profiler := evaluator.NewProfiler(); env.SetOptions(&evaluator.Options{Debugger: profiler}); ...; profiler.Report()
+type Profiler struct {
+	mutex     sync.Mutex
+	functions map[*ast.BlockStatement]*FunctionProfile
+}
+
+// Constructor function for profiler
+func NewProfiler() *Profiler {
+	return &Profiler{functions: make(map[*ast.BlockStatement]*FunctionProfile)}
+}
+
+func (profiler *Profiler) Before(statement ast.Statement, env *object.Environment) {}
+func (profiler *Profiler) After(statement ast.Statement, result object.Object)     {}
+
+// Counts the call of the function, and measures its time till the returned function is called
+// Functions are identified by their body, so that closures created from the same function literal are counted together
+func (profiler *Profiler) Call(function *object.Function) func() {
+	profiler.mutex.Lock()
+	profile, ok := profiler.functions[function.Body]
+	if !ok {
+		profile = &FunctionProfile{Name: profileName(function)}
+		profiler.functions[function.Body] = profile
+	}
+	profile.Calls++
+	profile.depth++
+	outermost := profile.depth == 1
+	profiler.mutex.Unlock()
+
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		profiler.mutex.Lock()
+		defer profiler.mutex.Unlock()
+		profile.depth--
+		if outermost {
+			profile.Time += elapsed
+		}
+	}
+}
+
+// Returns the profiles of the called functions, with the most time consuming first
+func (profiler *Profiler) Report() []FunctionProfile {
+	profiler.mutex.Lock()
+	defer profiler.mutex.Unlock()
+	profiles := []FunctionProfile{}
+	for _, profile := range profiler.functions {
+		profiles = append(profiles, FunctionProfile{Name: profile.Name, Calls: profile.Calls, Time: profile.Time})
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Time != profiles[j].Time {
+			return profiles[i].Time > profiles[j].Time
+		}
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles
+}
+
+// Name of the function along with the location of its body, as different functions can have the same name
+func profileName(function *object.Function) string {
+	name := function.Name
+	if name == "" {
+		name = "anonymous function"
+	}
+	start := function.Body.Span().Start
+	return fmt.Sprintf("%s (%d:%d)", name, start.Line, start.Column)
+}
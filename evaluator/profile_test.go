@@ -0,0 +1,16 @@
+package evaluator
+
+import "testing"
+
+func TestProfiler(t *testing.T) {
+	profiler := NewProfiler()
+	testEvalWithOptions(t, "let square = fn(x) { x * x }\nlet total = 0\nfor i in range(0, 10) { total = total + square(i) }\nlet fact = fn(n) { if n < 2 { return 1 }; n * fact(n - 1) }\nfact(5)", &Options{Debugger: profiler})
+
+	calls := map[string]int{}
+	for _, profile := range profiler.Report() {
+		calls[profile.Name] = profile.Calls
+	}
+	if calls["square (1:20)"] != 10 || calls["fact (4:18)"] != 5 || len(calls) != 2 {
+		t.Errorf("Expected 10 calls of square and 5 of fact, got %v", calls)
+	}
+}
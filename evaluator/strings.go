@@ -0,0 +1,219 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Builtin strings module
+// upper, lower, split and join are also available as top level builtins for backward compatibility
+// Example: import "strings"; strings.trim("  FroLang  ")
+var stringsModule = &object.Module{
+	Name: "strings",
+	Members: map[string]object.Object{
+		"upper":      &object.Builtin{Fn: upper},
+		"lower":      &object.Builtin{Fn: lower},
+		"split":      &object.Builtin{Fn: split},
+		"join":       &object.Builtin{Fn: join},
+		"trim":       &object.Builtin{Fn: trimmer("trim")},
+		"strip":      &object.Builtin{Fn: trimmer("strip")},
+		"replace":    &object.Builtin{Fn: replace},
+		"contains":   &object.Builtin{Fn: contains},
+		"repeat":     &object.Builtin{Fn: repeat},
+		"padStart":   &object.Builtin{Fn: padStart},
+		"padEnd":     &object.Builtin{Fn: padEnd},
+		"startsWith": &object.Builtin{Fn: affixMatcher("startsWith", strings.HasPrefix)},
+		"endsWith":   &object.Builtin{Fn: affixMatcher("endsWith", strings.HasSuffix)},
+		"lines":      &object.Builtin{Fn: splitter("lines", splitLines)},
+		"words":      &object.Builtin{Fn: splitter("words", strings.Fields)},
+	},
+}
+
+// Creates a builtin that returns a string with leading and trailing white spaces removed
+// If a string of characters is supplied, then any of those characters are removed instead of white spaces
+// Example: strip("--Fro!--", "-!") gives "Fro"
+func trimmer(name string) func(arguments ...object.Object) object.Object {
+	return func(arguments ...object.Object) object.Object {
+		if len(arguments) != 1 && len(arguments) != 2 {
+			return newError("Wrong number of arguments. Got=%d want=1 or 2", len(arguments))
+		}
+		str, ok := arguments[0].(*object.String)
+		if !ok {
+			return newError("First argument to %s must be STRING. Got %s", name, arguments[0].Type())
+		}
+		if len(arguments) == 1 {
+			return &object.String{Value: strings.TrimSpace(str.Value)}
+		}
+		chars, ok := arguments[1].(*object.String)
+		if !ok {
+			return newError("Characters to %s must be STRING. Got %s", name, arguments[1].Type())
+		}
+		return &object.String{Value: strings.Trim(str.Value, chars.Value)}
+	}
+}
+
+// Returns a string with all occurrences of old replaced by new
+func replace(arguments ...object.Object) object.Object {
+	if len(arguments) != 3 {
+		return newError("Wrong number of arguments. Got=%d want=3", len(arguments))
+	}
+	for _, argument := range arguments {
+		if argument.Type() != object.STRING_OBJ {
+			return newError("Arguments to replace must be STRING. Got %s", argument.Type())
+		}
+	}
+	str := arguments[0].(*object.String).Value
+	old := arguments[1].(*object.String).Value
+	new := arguments[2].(*object.String).Value
+	return &object.String{Value: strings.ReplaceAll(str, old, new)}
+}
+
+// Returns whether a string contains the substring
+func contains(arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ || arguments[1].Type() != object.STRING_OBJ {
+		return newError("Arguments to contains must be STRING. Got %s, %s", arguments[0].Type(), arguments[1].Type())
+	}
+	str := arguments[0].(*object.String).Value
+	substr := arguments[1].(*object.String).Value
+	return nativeToBooleanObject(strings.Contains(str, substr))
+}
+
+// Returns a string made of count copies of the supplied string
+func repeat(arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ {
+		return newError("First argument to repeat must be STRING. Got %s", arguments[0].Type())
+	}
+	if arguments[1].Type() != object.INTEGER_OBJ {
+		return newError("Count to repeat must be INTEGER. Got %s", arguments[1].Type())
+	}
+	count := arguments[1].(*object.Integer).Value
+	if count < 0 {
+		return newError("Count to repeat must be non-negative. Got %d", count)
+	}
+	str := arguments[0].(*object.String).Value
+	return &object.String{Value: strings.Repeat(str, count)}
+}
+
+// Validates the arguments of padStart and padEnd
+// Returns the string, width and fill as runes. Fill defaults to a space
+func padArguments(name string, arguments []object.Object) ([]rune, int, []rune, *object.Error) {
+	if len(arguments) != 2 && len(arguments) != 3 {
+		return nil, 0, nil, newError("Wrong number of arguments. Got=%d want=2 or 3", len(arguments))
+	}
+	str, ok := arguments[0].(*object.String)
+	if !ok {
+		return nil, 0, nil, newError("First argument to %s must be STRING. Got %s", name, arguments[0].Type())
+	}
+	width, ok := arguments[1].(*object.Integer)
+	if !ok {
+		return nil, 0, nil, newError("Width for %s must be INTEGER. Got %s", name, arguments[1].Type())
+	}
+	fill := " "
+	if len(arguments) == 3 {
+		fillString, ok := arguments[2].(*object.String)
+		if !ok {
+			return nil, 0, nil, newError("Fill for %s must be STRING. Got %s", name, arguments[2].Type())
+		}
+		if fillString.Value == "" {
+			return nil, 0, nil, newError("Fill for %s cannot be empty", name)
+		}
+		fill = fillString.Value
+	}
+	return []rune(str.Value), width.Value, []rune(fill), nil
+}
+
+// Returns the padding of the string to reach the width, by repeating the fill and truncating it
+func padding(str []rune, width int, fill []rune) string {
+	if len(str) >= width {
+		return ""
+	}
+	padding := make([]rune, width-len(str))
+	for index := range padding {
+		padding[index] = fill[index%len(fill)]
+	}
+	return string(padding)
+}
+
+// Returns the string padded at the start with fill, so that it has width characters
+// String which already has width characters is returned unchanged
+// Example: padStart("7", 3, "0") gives "007"
+func padStart(arguments ...object.Object) object.Object {
+	str, width, fill, err := padArguments("padStart", arguments)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: padding(str, width, fill) + string(str)}
+}
+
+// Returns the string padded at the end with fill, so that it has width characters
+// String which already has width characters is returned unchanged
+// Example: padEnd("ab", 5, "xy") gives "abxyx"
+func padEnd(arguments ...object.Object) object.Object {
+	str, width, fill, err := padArguments("padEnd", arguments)
+	if err != nil {
+		return err
+	}
+	return &object.String{Value: string(str) + padding(str, width, fill)}
+}
+
+// Creates a builtin that checks whether a string has the affix, using the check function
+// Affix can also be an array of strings, in which case the string should have any of them
+func affixMatcher(name string, check func(str string, affix string) bool) func(arguments ...object.Object) object.Object {
+	return func(arguments ...object.Object) object.Object {
+		if len(arguments) != 2 {
+			return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+		}
+		str, ok := arguments[0].(*object.String)
+		if !ok {
+			return newError("First argument to %s must be STRING. Got %s", name, arguments[0].Type())
+		}
+		affixes := []object.Object{arguments[1]}
+		if array, ok := arguments[1].(*object.Array); ok {
+			affixes = array.Elements
+		}
+		matched := false
+		for _, affix := range affixes {
+			affixString, ok := affix.(*object.String)
+			if !ok {
+				return newError("Second argument to %s must be STRING or ARRAY of STRING. Got %s", name, affix.Type())
+			}
+			matched = matched || check(str.Value, affixString.Value)
+		}
+		return nativeToBooleanObject(matched)
+	}
+}
+
+// Creates a builtin that splits a string into an array of strings, using the split function
+func splitter(name string, split func(str string) []string) func(arguments ...object.Object) object.Object {
+	return func(arguments ...object.Object) object.Object {
+		if len(arguments) != 1 {
+			return newError("Wrong number of arguments. Got=%d want=1", len(arguments))
+		}
+		str, ok := arguments[0].(*object.String)
+		if !ok {
+			return newError("Argument to %s must be STRING. Got %s", name, arguments[0].Type())
+		}
+		elements := []object.Object{}
+		for _, part := range split(str.Value) {
+			elements = append(elements, &object.String{Value: part})
+		}
+		return &object.Array{Elements: elements}
+	}
+}
+
+// Splits the string into lines, which may end with \n or \r\n
+// Newline at the end of the string doesn't make an empty last line
+func splitLines(str string) []string {
+	lines := strings.Split(strings.TrimSuffix(strings.ReplaceAll(str, "\r\n", "\n"), "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return []string{}
+	}
+	return lines
+}
@@ -0,0 +1,59 @@
+package evaluator
+
+import (
+	"time"
+
+	"github.com/mochatek/frolang/object"
+)
+
+// Builtin time module
+// Timestamps are integers representing milliseconds since unix epoch
+// Layouts follow Go's reference time: Mon Jan 2 15:04:05 MST 2006
+// Example: import "time"; time.format(time.now(), "2006-01-02")
+var timeModule = &object.Module{
+	Name: "time",
+	Members: map[string]object.Object{
+		"now":    &object.Builtin{Fn: timeNow},
+		"format": &object.Builtin{Fn: timeFormat},
+		"parse":  &object.Builtin{Fn: timeParse},
+	},
+}
+
+// Returns the current timestamp
+func timeNow(arguments ...object.Object) object.Object {
+	if len(arguments) != 0 {
+		return newError("Wrong number of arguments. Got=%d want=0", len(arguments))
+	}
+	return &object.Integer{Value: int(time.Now().UnixMilli())}
+}
+
+// Returns the timestamp formatted as per the layout in UTC
+func timeFormat(arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	if arguments[0].Type() != object.INTEGER_OBJ {
+		return newError("Timestamp to format must be INTEGER. Got %s", arguments[0].Type())
+	}
+	if arguments[1].Type() != object.STRING_OBJ {
+		return newError("Layout to format must be STRING. Got %s", arguments[1].Type())
+	}
+	timestamp := time.UnixMilli(int64(arguments[0].(*object.Integer).Value)).UTC()
+	return &object.String{Value: timestamp.Format(arguments[1].(*object.String).Value)}
+}
+
+// Returns the timestamp of a time string formatted as per the layout
+// Time string is treated as UTC, if it doesn't have a zone
+func timeParse(arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("Wrong number of arguments. Got=%d want=2", len(arguments))
+	}
+	if arguments[0].Type() != object.STRING_OBJ || arguments[1].Type() != object.STRING_OBJ {
+		return newError("Arguments to parse must be STRING. Got %s, %s", arguments[0].Type(), arguments[1].Type())
+	}
+	timestamp, err := time.Parse(arguments[1].(*object.String).Value, arguments[0].(*object.String).Value)
+	if err != nil {
+		return newError("Cannot parse time: %s", err)
+	}
+	return &object.Integer{Value: int(timestamp.UnixMilli())}
+}
@@ -0,0 +1,20 @@
+package evaluator
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/mochatek/frolang/ast"
+)
+
+// Serializes the writes to the trace writers, as spawned tasks log their nodes from their own goroutines
+var traceMutex sync.Mutex
+
+// Logs the type of the node along with the span of source code it was parsed from
+func traceNode(trace io.Writer, node ast.Node) {
+	traceMutex.Lock()
+	defer traceMutex.Unlock()
+	fmt.Fprintf(trace, "TRACE %s %s\n", reflect.TypeOf(node).Elem().Name(), node.Span())
+}
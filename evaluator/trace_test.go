@@ -0,0 +1,35 @@
+package evaluator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	var trace strings.Builder
+	testEvalWithOptions(t, `let x = 1 + 2`, &Options{Trace: &trace})
+
+	expected := "TRACE Program 1:1-1:13\n" +
+		"TRACE LetStatement 1:1-1:13\n" +
+		"TRACE InfixExpression 1:9-1:13\n" +
+		"TRACE IntegerLiteral 1:9-1:9\n" +
+		"TRACE IntegerLiteral 1:13-1:13\n"
+	if trace.String() != expected {
+		t.Errorf("Expected trace:\n%s\ngot:\n%s", expected, trace.String())
+	}
+}
+
+// Spawned tasks log to the trace writer of their run from their own goroutines, one line at a time. Run with -race
+func TestTraceOfSpawnedTasks(t *testing.T) {
+	var trace strings.Builder
+	testEvalWithOptions(t, `let tasks = [spawn(fn(x) { x * 2 }, i) for i in range(0, 8)]; map(tasks, wait)`, &Options{Trace: &trace})
+	lines := strings.Split(strings.TrimSuffix(trace.String(), "\n"), "\n")
+	if count := strings.Count(trace.String(), "TRACE InfixExpression"); count != 8 {
+		t.Errorf("Expected the body of each task to be traced, got %d infix expressions", count)
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "TRACE ") {
+			t.Errorf("Expected every line to be a trace, got %q", line)
+		}
+	}
+}
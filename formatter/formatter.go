@@ -0,0 +1,488 @@
+package formatter
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mochatek/frolang/ast"
+	"github.com/mochatek/frolang/lexer"
+	"github.com/mochatek/frolang/parser"
+	"github.com/mochatek/frolang/token"
+)
+
+// Number of spaces used for each level of indentation
+var IndentWidth = 4
+
+// Hash literals longer than this are split into one pair per line
+var MaxLineWidth = 80
+
+// Blocks with a single short statement are kept in one line, if the statement fits within this width
+var MaxInlineWidth = 60
+
+// Comment in the source code, along with its position
+type comment struct {
+	text  string
+	start ast.Position
+	end   ast.Position
+}
+
+// Printer holds the state while the program is being formatted
+// Comments are not part of the AST, so they are printed before the statement following them
+type printer struct {
+	indent   int
+	comments []comment
+}
+
+// Formats the source code with consistent indentation and spacing
+// Source code is returned as it is along with the errors, if it cannot be parsed
+// Formatting the formatted code again doesn't change it
+func Format(source string) (string, []string) {
+	par := parser.New(lexer.New(source))
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		return source, par.ErrorsWithSource(source)
+	}
+	printer := &printer{comments: scanComments(source)}
+	lines := printer.statements(program.Statements, nil)
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// Collects the comments in the source code
+// Line and column are counted the same way as the lexer, so that they can be compared with the spans
+func scanComments(source string) []comment {
+	comments := []comment{}
+	line, column := 1, 0
+	inString := false
+	for index := 0; index < len(source); index++ {
+		char := source[index]
+		column++
+		switch {
+		case inString:
+			inString = char != '"'
+		case char == '"':
+			inString = true
+		case char == '\n':
+			line++
+			column = 0
+		case char == '/' && index+1 < len(source) && source[index+1] == '*':
+			start := ast.Position{Line: line, Column: column}
+			end := strings.Index(source[index:], "*/")
+			if end == -1 {
+				end = len(source) - index - 2
+			}
+			text := source[index : index+end+2]
+			for _, char := range text[1:] {
+				column++
+				if char == '\n' {
+					line++
+					column = 0
+				}
+			}
+			index += len(text) - 1
+			comments = append(comments, comment{text: text, start: start, end: ast.Position{Line: line, Column: column}})
+		}
+	}
+	return comments
+}
+
+// Helper function to check whether a position comes before another
+func before(position ast.Position, other ast.Position) bool {
+	return position.Line < other.Line || (position.Line == other.Line && position.Column < other.Column)
+}
+
+// Returns the indentation for the current level
+func (printer *printer) pad() string {
+	return strings.Repeat(" ", printer.indent*IndentWidth)
+}
+
+// Checks whether there is a comment yet to be printed within the span
+func (printer *printer) hasComment(span ast.Span) bool {
+	for _, comment := range printer.comments {
+		if before(comment.start, span.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// Formats the statements into indented lines along with the comments before them
+// Blank lines between statements are preserved, but multiple blank lines are collapsed into one
+// If end is nil, then all the remaining comments are printed after the statements
+func (printer *printer) statements(statements []ast.Statement, end *ast.Position) []string {
+	lines := []string{}
+	lastLine := 0
+	// Index and length of the line, which may need a semicolon before the next statement
+	open, openLength := -1, 0
+
+	// Adds the text starting at the given line of source code, after a blank line if there was one
+	add := func(text string, startLine int, endLine int) {
+		if lastLine != 0 && startLine > lastLine+1 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, printer.pad()+text)
+		lastLine = endLine
+	}
+	// Prints the comments before the position, with the ones in the same line appended to the last line
+	flush := func(position *ast.Position) {
+		for len(printer.comments) != 0 && (position == nil || before(printer.comments[0].start, *position)) {
+			comment := printer.comments[0]
+			printer.comments = printer.comments[1:]
+			if len(lines) != 0 && comment.start.Line == lastLine {
+				lines[len(lines)-1] += " " + comment.text
+				lastLine = comment.end.Line
+			} else {
+				add(comment.text, comment.start.Line, comment.end.Line)
+			}
+		}
+	}
+
+	for _, statement := range statements {
+		span := statement.Span()
+		flush(&span.Start)
+		text := printer.statement(statement)
+		// An if/match followed by a statement starting with one of these would be parsed as a single expression
+		if open != -1 && strings.ContainsAny(text[:1], "([-") {
+			lines[open] = lines[open][:openLength] + ";" + lines[open][openLength:]
+		}
+		terminator := terminatorOf(statement)
+		add(text+terminator, span.Start.Line, span.End.Line)
+		open = -1
+		if terminator == "" && isExpression(statement) {
+			open, openLength = len(lines)-1, len(lines[len(lines)-1])
+		}
+	}
+	flush(end)
+	return lines
+}
+
+// Returns the text to end the statement with
+// Statements ending with a block doesn't need semicolon
+func terminatorOf(statement ast.Statement) string {
+	switch statement := statement.(type) {
+	case *ast.ForStatement, *ast.WhileStatement, *ast.TryStatement, *ast.WithStatement:
+		return ""
+	case *ast.ExpressionStatement:
+		switch statement.Expression.(type) {
+		case *ast.IfExpression, *ast.MatchExpression:
+			return ""
+		}
+	}
+	return ";"
+}
+
+// Helper function to check for expression statement
+func isExpression(statement ast.Statement) bool {
+	_, ok := statement.(*ast.ExpressionStatement)
+	return ok
+}
+
+// Formats the block with its statements indented by one level
+// A block having a single short statement without comments is kept in one line
+func (printer *printer) block(block *ast.BlockStatement) string {
+	printer.indent++
+	defer func() { printer.indent-- }()
+	outerPad := strings.Repeat(" ", (printer.indent-1)*IndentWidth)
+
+	if len(block.Statements) == 1 && isSimple(block.Statements[0]) && !printer.hasComment(block.Span()) {
+		text := printer.statement(block.Statements[0])
+		if !strings.Contains(text, "\n") && len(text) <= MaxInlineWidth {
+			return "{ " + text + " }"
+		}
+		return "{\n" + printer.pad() + text + terminatorOf(block.Statements[0]) + "\n" + outerPad + "}"
+	}
+
+	end := block.Span().End
+	lines := printer.statements(block.Statements, &end)
+	if len(lines) == 0 {
+		return "{}"
+	}
+	return "{\n" + strings.Join(lines, "\n") + "\n" + outerPad + "}"
+}
+
+// Checks whether the statement can be kept in one line within a block
+func isSimple(statement ast.Statement) bool {
+	switch statement.(type) {
+	case *ast.ExpressionStatement, *ast.ReturnStatement, *ast.BreakStatement, *ast.ContinueStatement, *ast.YieldStatement:
+		return true
+	}
+	return false
+}
+
+// Formats the statement without indentation and terminating semicolon
+func (printer *printer) statement(statement ast.Statement) string {
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		return "let " + statement.Name.Value + " = " + printer.expression(statement.Value)
+	case *ast.ReturnStatement:
+		if statement.ReturnValue == nil {
+			return "return"
+		}
+		return "return " + printer.expression(statement.ReturnValue)
+	case *ast.YieldStatement:
+		return "yield " + printer.expression(statement.Value)
+	case *ast.DeferStatement:
+		return "defer " + printer.expression(statement.Expression)
+	case *ast.ExpressionStatement:
+		return printer.expression(statement.Expression)
+	case *ast.BreakStatement:
+		return "break"
+	case *ast.ContinueStatement:
+		return "continue"
+	case *ast.ForStatement:
+		text := "for " + statement.Element.Value + " in " + printer.expression(statement.Iterator)
+		if statement.Guard != nil {
+			text += " if " + printer.expression(statement.Guard)
+		}
+		return text + " " + printer.block(statement.Body)
+	case *ast.WhileStatement:
+		return "while (" + printer.expression(statement.Condition) + ") " + printer.block(statement.Body)
+	case *ast.TryStatement:
+		text := "try " + printer.block(statement.Try)
+		text += " catch (" + statement.Error.Value + ") " + printer.block(statement.Catch)
+		if statement.Finally != nil {
+			text += " finally " + printer.block(statement.Finally)
+		}
+		return text
+	case *ast.WithStatement:
+		return "with " + printer.expression(statement.Resource) + " as " + statement.Name.Value + " " + printer.block(statement.Body)
+	case *ast.ImportStatement:
+		text := "import " + quote(statement.Path.Value)
+		if statement.Alias != nil {
+			text += " as " + statement.Alias.Value
+		}
+		return text
+	case *ast.ExportStatement:
+		return "export " + printer.statement(statement.Statement)
+	}
+	return statement.String()
+}
+
+// Precedence of literals, which never need parentheses around them
+const ATOM = parser.INDEX + 1
+
+// Returns the precedence of the expression as per the parser
+func precedenceOf(expression ast.Expression) int {
+	switch expression := expression.(type) {
+	case *ast.InfixExpression:
+		return parser.Precedence(expression.Operator)
+	case *ast.ComparisonExpression:
+		return parser.LESS_GREATER
+	case *ast.AssignExpression:
+		return parser.ASSIGN
+	case *ast.PrefixExpression:
+		return parser.PREFIX
+	case *ast.CallExpression:
+		return parser.CALL
+	case *ast.IndexExpression, *ast.SliceExpression, *ast.MemberExpression, *ast.PostfixExpression:
+		return parser.INDEX
+	}
+	return ATOM
+}
+
+// Formats the operand, wrapped within parentheses if its precedence is not above the minimum
+func (printer *printer) operand(expression ast.Expression, minimum int) string {
+	text := printer.expression(expression)
+	if precedenceOf(expression) <= minimum {
+		return "(" + text + ")"
+	}
+	return text
+}
+
+// Formats the expression with only the parentheses needed to parse it back the same way
+func (printer *printer) expression(expression ast.Expression) string {
+	switch expression := expression.(type) {
+	case *ast.Identifier:
+		return expression.Value
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.BooleanLiteral:
+		return expression.TokenLiteral()
+	case *ast.StringLiteral:
+		return quote(expression.Value)
+	case *ast.PrefixExpression:
+		text := printer.operand(expression.Right, parser.PREFIX-1)
+		// Avoid merging with the operand's operator, which would make it a decrement
+		if expression.Operator == "-" && strings.HasPrefix(text, "-") {
+			text = "(" + text + ")"
+		}
+		return expression.Operator + text
+	case *ast.PostfixExpression:
+		return expression.Variable.Value + expression.Operator
+	case *ast.InfixExpression:
+		precedence := parser.Precedence(expression.Operator)
+		minimum := precedence - 1
+		if precedence == parser.LESS_GREATER {
+			// Relational operands are parsed as chained comparison, unless grouped
+			minimum = precedence
+		}
+		return printer.operand(expression.Left, minimum) + " " + expression.Operator + " " + printer.operand(expression.Right, precedence)
+	case *ast.ComparisonExpression:
+		text := printer.operand(expression.Operands[0], parser.LESS_GREATER)
+		for index, operator := range expression.Operators {
+			text += " " + operator + " " + printer.operand(expression.Operands[index+1], parser.LESS_GREATER)
+		}
+		return text
+	case *ast.AssignExpression:
+		target := expression.Variable.Value
+		if expression.Slice != nil {
+			target = printer.expression(expression.Slice)
+		}
+		return target + " = " + printer.expression(expression.Value)
+	case *ast.CallExpression:
+		return printer.operand(expression.Function, parser.CALL-1) + "(" + printer.list(expression.Arguments) + ")"
+	case *ast.IndexExpression:
+		return printer.operand(expression.Array, parser.CALL-1) + expression.Token.Literal + printer.expression(expression.Index) + "]"
+	case *ast.SliceExpression:
+		text := printer.operand(expression.Left, parser.CALL-1) + expression.Token.Literal
+		if expression.Start != nil {
+			text += printer.expression(expression.Start)
+		}
+		text += ":"
+		if expression.End != nil {
+			text += printer.expression(expression.End)
+		}
+		if expression.Step != nil {
+			text += ":" + printer.expression(expression.Step)
+		}
+		return text + "]"
+	case *ast.MemberExpression:
+		return printer.operand(expression.Object, parser.CALL-1) + expression.Token.Literal + expression.Property.Value
+	case *ast.SpreadExpression:
+		return "..." + printer.expression(expression.Value)
+	case *ast.ArrayLiteral:
+		return "[" + printer.list(expression.Elements) + "]"
+	case *ast.TupleLiteral:
+		if len(expression.Elements) == 1 {
+			return "(" + printer.expression(expression.Elements[0]) + ",)"
+		}
+		return "(" + printer.list(expression.Elements) + ")"
+	case *ast.HashLiteral:
+		return printer.hash(expression)
+	case *ast.ComprehensionExpression:
+		text := printer.expression(expression.Value)
+		if expression.Key != nil {
+			text = printer.expression(expression.Key) + ": " + text
+		}
+		text += " for " + expression.Element.Value + " in " + printer.expression(expression.Iterator)
+		if expression.Guard != nil {
+			text += " if " + printer.expression(expression.Guard)
+		}
+		if expression.Key != nil {
+			return "{" + text + "}"
+		}
+		return "[" + text + "]"
+	case *ast.FunctionLiteral:
+		parameters := []string{}
+		for _, parameter := range expression.Parameters {
+			parameters = append(parameters, parameter.Value)
+		}
+		return "fn(" + strings.Join(parameters, ", ") + ") " + printer.block(expression.Body)
+	case *ast.IfExpression:
+		text := "if (" + printer.expression(expression.Condition) + ") " + printer.block(expression.Consequence)
+		if expression.Alternate != nil {
+			text += " else " + printer.block(expression.Alternate)
+		}
+		return text
+	case *ast.MatchExpression:
+		return printer.match(expression)
+	}
+	return expression.String()
+}
+
+// Formats the expressions separated by comma
+func (printer *printer) list(expressions []ast.Expression) string {
+	texts := []string{}
+	for _, expression := range expressions {
+		texts = append(texts, printer.expression(expression))
+	}
+	return strings.Join(texts, ", ")
+}
+
+// Formats the match expression with one arm per line
+// Arm having an expression as body is kept as expression
+func (printer *printer) match(expression *ast.MatchExpression) string {
+	subject := "match " + printer.expression(expression.Subject)
+	if len(expression.Arms) == 0 {
+		return subject + " {}"
+	}
+	printer.indent++
+	arms := []string{}
+	for _, arm := range expression.Arms {
+		text := printer.pad() + printer.expression(arm.Pattern)
+		if arm.Guard != nil {
+			text += " if " + printer.expression(arm.Guard)
+		}
+		if arm.Body.Token.Type == token.L_BRACE {
+			text += " => " + printer.block(arm.Body)
+		} else {
+			text += " => " + printer.statement(arm.Body.Statements[0])
+		}
+		arms = append(arms, text+",")
+	}
+	printer.indent--
+	return subject + " {\n" + strings.Join(arms, "\n") + "\n" + printer.pad() + "}"
+}
+
+// Formats the hash literal in the order of the pairs in source code
+// Hash which doesn't fit in a line is split into one pair per line, with the values aligned
+func (printer *printer) hash(hash *ast.HashLiteral) string {
+	keys := []ast.Expression{}
+	for key := range hash.Pairs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return before(keys[i].Span().Start, keys[j].Span().Start) })
+
+	printer.indent++
+	pairKeys, values := []string{}, []string{}
+	multiline := false
+	width := 2
+	for _, key := range keys {
+		value := hash.Pairs[key]
+		var keyText, valueText string
+		if literal, ok := key.(*ast.StringLiteral); ok && literal.Token.Type == token.IDENTIFIER {
+			// Shorthand for a pair having the identifier's name as key
+			keyText = literal.Value
+		} else {
+			keyText = printer.expression(key) + ":"
+			valueText = printer.expression(value)
+		}
+		pairKeys = append(pairKeys, keyText)
+		values = append(values, valueText)
+		multiline = multiline || strings.Contains(keyText+valueText, "\n")
+		width += len(keyText) + len(valueText) + 3
+	}
+	pad := printer.pad()
+	printer.indent--
+
+	if len(keys) == 0 {
+		return "{}"
+	}
+	if !multiline && printer.indent*IndentWidth+width <= MaxLineWidth {
+		pairs := []string{}
+		for index, key := range pairKeys {
+			pairs = append(pairs, strings.TrimSpace(key+" "+values[index]))
+		}
+		return "{" + strings.Join(pairs, ", ") + "}"
+	}
+	keyWidth := 0
+	for index, key := range pairKeys {
+		if values[index] != "" && len(key) > keyWidth {
+			keyWidth = len(key)
+		}
+	}
+	lines := []string{}
+	for index, key := range pairKeys {
+		if values[index] == "" {
+			lines = append(lines, pad+key+",")
+		} else {
+			lines = append(lines, pad+key+strings.Repeat(" ", keyWidth-len(key)+1)+values[index]+",")
+		}
+	}
+	return "{\n" + strings.Join(lines, "\n") + "\n" + printer.pad() + "}"
+}
+
+// Helper function to wrap the string within double quotes
+func quote(value string) string {
+	return "\"" + value + "\""
+}
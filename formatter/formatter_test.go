@@ -0,0 +1,69 @@
+package formatter
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	messy := `let   add=fn(a,b){a+b}
+/* comment about x */
+let x = add( 1,2 ) ;
+if x>2{print("big")}else{
+print("small")
+}
+let config = {"name": "frolang", "version": 1, "tags": ["a", "b", "c"], "description": "a small language"}
+for i in range(0,3) { if i == 1 { continue }
+print(i) }
+`
+	expected := `let add = fn(a, b) { a + b };
+/* comment about x */
+let x = add(1, 2);
+if (x > 2) { print("big") } else { print("small") }
+let config = {
+    "name":        "frolang",
+    "version":     1,
+    "tags":        ["a", "b", "c"],
+    "description": "a small language",
+};
+for i in range(0, 3) {
+    if (i == 1) { continue }
+    print(i);
+}
+`
+	formatted, errors := Format(messy)
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+	if formatted != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, formatted)
+	}
+	if again, _ := Format(formatted); again != formatted {
+		t.Errorf("Expected formatted code to stay the same, got:\n%s", again)
+	}
+}
+
+// Formatting the formatted code again must not change it
+func TestFormatIsIdempotent(t *testing.T) {
+	inputs := []string{
+		"let f = fn(x) {\nif x { return 1 }\nlet y = [i * 2 for i in range(0, x) if i > 1]\nmatch y { [a, ...rest] => a, _ => 0 }\n}",
+		"try { risky() } catch (err) { print(err) } finally { done() }",
+		"import \"math\" as m\nexport let area = fn(r) { m.pi * r * r }",
+		"let t = (1,)\nlet h = {(1, 2): \"pair\", x}\nitems[1:3] = [0]\ncount++",
+		"",
+	}
+	for _, input := range inputs {
+		once, errors := Format(input)
+		if len(errors) != 0 {
+			t.Fatalf("%q: unexpected errors: %v", input, errors)
+		}
+		if twice, _ := Format(once); twice != once {
+			t.Errorf("Formatting is not idempotent.\nFirst:\n%s\nSecond:\n%s", once, twice)
+		}
+	}
+}
+
+func TestFormatParseError(t *testing.T) {
+	source := "let x = "
+	formatted, errors := Format(source)
+	if formatted != source || len(errors) == 0 {
+		t.Errorf("Expected source to be returned with errors, got %q %v", formatted, errors)
+	}
+}
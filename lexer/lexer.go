@@ -39,16 +39,18 @@ func (lexer *Lexer) readChar() {
 	lexer.col += 1
 }
 
+// Return the character at peekPosition without advancing
+func (lexer *Lexer) peekChar() byte {
+	if lexer.peekPosition >= len(lexer.input) {
+		return 0
+	}
+	return lexer.input[lexer.peekPosition]
+}
+
 // Equate character at peekPosition to what is expected
 // Return equated result
 func (lexer *Lexer) peekCharIs(expectedChar byte) bool {
-	var peekChar byte
-	if lexer.peekPosition >= len(lexer.input) {
-		peekChar = 0
-	} else {
-		peekChar = lexer.input[lexer.peekPosition]
-	}
-	return peekChar == expectedChar
+	return lexer.peekChar() == expectedChar
 }
 
 // Continue reading characters until assertion on `char` fails
@@ -87,9 +89,21 @@ func (lexer *Lexer) ReadToken() token.Token {
 	case 0:
 		tok = createToken(token.EOF, lexer.char, location)
 	case '+':
-		tok = createToken(token.PLUS, lexer.char, location)
+		if lexer.isPostfixOperator() {
+			char := lexer.char
+			lexer.readChar()
+			tok = token.Token{Type: token.INCREMENT, Literal: string(char) + string(lexer.char), Location: location}
+		} else {
+			tok = createToken(token.PLUS, lexer.char, location)
+		}
 	case '-':
-		tok = createToken(token.MINUS, lexer.char, location)
+		if lexer.isPostfixOperator() {
+			char := lexer.char
+			lexer.readChar()
+			tok = token.Token{Type: token.DECREMENT, Literal: string(char) + string(lexer.char), Location: location}
+		} else {
+			tok = createToken(token.MINUS, lexer.char, location)
+		}
 	case '(':
 		tok = createToken(token.L_PAREN, lexer.char, location)
 	case ')':
@@ -133,6 +147,10 @@ func (lexer *Lexer) ReadToken() token.Token {
 			char := lexer.char
 			lexer.readChar()
 			tok = token.Token{Type: token.EQ, Literal: string(char) + string(lexer.char), Location: location}
+		} else if lexer.peekCharIs('>') {
+			char := lexer.char
+			lexer.readChar()
+			tok = token.Token{Type: token.ARROW, Literal: string(char) + string(lexer.char), Location: location}
 		} else {
 			tok = createToken(token.ASSIGN, lexer.char, location)
 		}
@@ -160,28 +178,96 @@ func (lexer *Lexer) ReadToken() token.Token {
 		} else {
 			tok = createToken(token.GT, lexer.char, location)
 		}
+	case '?':
+		if lexer.peekCharIs('?') {
+			char := lexer.char
+			lexer.readChar()
+			tok = token.Token{Type: token.COALESCE, Literal: string(char) + string(lexer.char), Location: location}
+		} else if lexer.peekCharIs('.') {
+			char := lexer.char
+			lexer.readChar()
+			tok = token.Token{Type: token.Q_DOT, Literal: string(char) + string(lexer.char), Location: location}
+		} else if lexer.peekCharIs('[') {
+			char := lexer.char
+			lexer.readChar()
+			tok = token.Token{Type: token.Q_BRACKET, Literal: string(char) + string(lexer.char), Location: location}
+		} else {
+			tok = createToken(token.ILLEGAL, lexer.char, location)
+		}
 	case '"':
 		tok.Type = token.STRING
 		tok.Literal = lexer.readString()
+		tok.Location = location
 	default:
 		if isLetter(lexer.char) {
-			word := lexer.readAheadIfPeekChar(isLetter)
+			word := lexer.readAheadIfPeekChar(isIdentifierChar)
 			tokenType := resolveType(word) // word is identifier/keyword ?
 			tok = token.Token{Type: tokenType, Literal: word, Location: location}
+			tok.End = lexer.previousLocation()
 			return tok
+		} else if lexer.char == '.' && lexer.peekCharIs('.') && lexer.peekPosition+1 < len(lexer.input) && lexer.input[lexer.peekPosition+1] == '.' {
+			lexer.readChar()
+			lexer.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: token.ELLIPSIS, Location: location}
+		} else if lexer.char == '.' && !isDigit(lexer.peekChar()) {
+			tok = createToken(token.DOT, lexer.char, location)
 		} else if isNumber(lexer.char) {
 			number := lexer.readAheadIfPeekChar(isNumber)
 			numberType := resolveNumberType(number)
+			if isLetter(lexer.char) {
+				suffix := lexer.readAheadIfPeekChar(isLetter)
+				numberType = resolveSuffixType(numberType, suffix)
+				number += suffix
+			}
 			tok = token.Token{Type: numberType, Literal: number, Location: location}
+			tok.End = lexer.previousLocation()
 			return tok
+		} else {
+			tok = createToken(token.ILLEGAL, lexer.char, location)
 		}
-		tok = createToken(token.ILLEGAL, lexer.char, location)
 	}
 
+	tok.End = fmt.Sprintf("%d:%d", lexer.line, lexer.col)
 	lexer.readChar()
 	return tok
 }
 
+// Reads all the remaining tokens of the input and returns them, including the final EOF token
+// Comments are returned as the parser sees them: /* followed by the tokens inside it and */
+func (lexer *Lexer) Tokens() []token.Token {
+	tokens := []token.Token{}
+	for {
+		tok := lexer.ReadToken()
+		tokens = append(tokens, tok)
+		if tok.Type == token.EOF {
+			return tokens
+		}
+	}
+}
+
+// Checks whether the current and next characters form a postfix ++/--
+// It should follow an identifier (or ] of an index) without space, and no operand should follow it on the same line
+// Otherwise, the characters are separate operators. ie, a--b is a - (-b)
+func (lexer *Lexer) isPostfixOperator() bool {
+	if lexer.curPosition == 0 || !lexer.peekCharIs(lexer.char) {
+		return false
+	}
+	if previous := lexer.input[lexer.curPosition-1]; !isIdentifierChar(previous) && previous != ']' {
+		return false
+	}
+	next := lexer.peekPosition + 1
+	for next < len(lexer.input) && (lexer.input[next] == ' ' || lexer.input[next] == '\t') {
+		next += 1
+	}
+	return next >= len(lexer.input) || !startsOperand(lexer.input[next])
+}
+
+// Returns the location of the character before the current one
+// Used for the end of tokens which are read until the character after them
+func (lexer *Lexer) previousLocation() string {
+	return fmt.Sprintf("%d:%d", lexer.line, lexer.col-1)
+}
+
 // Advance to next character if `char` is whitespace
 // Increment line counter if we hit new line character and reset col to 0
 func (lexer *Lexer) skipWhiteSpace() {
@@ -204,6 +290,21 @@ func isLetter(char byte) bool {
 	return ('a' <= char && char <= 'z') || ('A' <= char && char <= 'Z') || char == '_'
 }
 
+// Helper function to check for valid character of an identifier after its first character
+func isIdentifierChar(char byte) bool {
+	return isLetter(char) || isDigit(char)
+}
+
+// Helper function to check for the first character of an operand. ie, identifier, number, string, group or array
+func startsOperand(char byte) bool {
+	return isIdentifierChar(char) || char == '(' || char == '[' || char == '"' || char == '!'
+}
+
+// Helper function to check for decimal digit
+func isDigit(char byte) bool {
+	return '0' <= char && char <= '9'
+}
+
 // Helper function to check for valid digit
 func isNumber(char byte) bool {
 	return '0' <= char && char <= '9' || char == '.' || char == '-'
@@ -224,3 +325,15 @@ func resolveNumberType(number string) token.TokenType {
 	}
 	return token.INTEGER
 }
+
+// Helper function to get the type of a number having suffix
+// Suffix f makes the number float, whereas i keeps it integer. Any other suffix is illegal
+func resolveSuffixType(numberType token.TokenType, suffix string) token.TokenType {
+	switch {
+	case suffix == "f" && numberType != token.ILLEGAL:
+		return token.FLOAT
+	case suffix == "i" && numberType == token.INTEGER:
+		return token.INTEGER
+	}
+	return token.ILLEGAL
+}
@@ -0,0 +1,60 @@
+package lexer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mochatek/frolang/token"
+)
+
+// Returns the types of the tokens of the input, without EOF
+func tokenTypes(input string) []token.TokenType {
+	lexer := New(input)
+	types := []token.TokenType{}
+	for tok := lexer.ReadToken(); tok.Type != token.EOF; tok = lexer.ReadToken() {
+		types = append(types, tok.Type)
+	}
+	return types
+}
+
+func TestPostfixOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []token.TokenType
+	}{
+		{"a++", []token.TokenType{token.IDENTIFIER, token.INCREMENT}},
+		{"a--", []token.TokenType{token.IDENTIFIER, token.DECREMENT}},
+		{"a--;", []token.TokenType{token.IDENTIFIER, token.DECREMENT, token.SEMICOLON}},
+		{"a--\nb", []token.TokenType{token.IDENTIFIER, token.DECREMENT, token.IDENTIFIER}},
+		{"a-- - 1", []token.TokenType{token.IDENTIFIER, token.DECREMENT, token.MINUS, token.INTEGER}},
+		{"a--b", []token.TokenType{token.IDENTIFIER, token.MINUS, token.MINUS, token.IDENTIFIER}},
+		{"a-- b", []token.TokenType{token.IDENTIFIER, token.MINUS, token.MINUS, token.IDENTIFIER}},
+		{"a --b", []token.TokenType{token.IDENTIFIER, token.MINUS, token.MINUS, token.IDENTIFIER}},
+		{"a--(b)", []token.TokenType{token.IDENTIFIER, token.MINUS, token.MINUS, token.L_PAREN, token.IDENTIFIER, token.R_PAREN}},
+		{"a[0]++", []token.TokenType{token.IDENTIFIER, token.L_BRACKET, token.INTEGER, token.R_BRACKET, token.INCREMENT}},
+		{"--a", []token.TokenType{token.MINUS, token.MINUS, token.IDENTIFIER}},
+	}
+	for _, test := range tests {
+		if types := tokenTypes(test.input); !reflect.DeepEqual(types, test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.input, test.expected, types)
+		}
+	}
+}
+
+func TestTokens(t *testing.T) {
+	expected := []token.Token{
+		{Type: token.LET, Literal: "let", Location: "1:1", End: "1:3"},
+		{Type: token.IDENTIFIER, Literal: "x", Location: "1:5", End: "1:5"},
+		{Type: token.ASSIGN, Literal: "=", Location: "1:7", End: "1:7"},
+		{Type: token.FLOAT, Literal: "5f", Location: "1:9", End: "1:10"},
+		{Type: token.PLUS, Literal: "+", Location: "1:12", End: "1:12"},
+		{Type: token.INTEGER, Literal: "2i", Location: "1:14", End: "1:15"},
+		{Type: token.SEMICOLON, Literal: ";", Location: "1:16", End: "1:16"},
+		{Type: token.STRING, Literal: "hi", Location: "2:1", End: "2:4"},
+		{Type: token.FLOAT, Literal: "1.5", Location: "2:6", End: "2:8"},
+		{Type: token.EOF, Literal: "\x00", Location: "2:9", End: "2:9"},
+	}
+	if tokens := New("let x = 5f + 2i;\n\"hi\" 1.5").Tokens(); !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Expected %v, got %v", expected, tokens)
+	}
+}
@@ -4,9 +4,10 @@ import (
 	"fmt"
 	"os"
 	"runtime"
-	"strings"
 
+	"github.com/mochatek/frolang/analyzer"
 	"github.com/mochatek/frolang/evaluator"
+	"github.com/mochatek/frolang/formatter"
 	"github.com/mochatek/frolang/lexer"
 	"github.com/mochatek/frolang/object"
 	"github.com/mochatek/frolang/parser"
@@ -16,13 +17,17 @@ import (
 var RESET = "\033[0m"
 var RED = "\033[31m"
 var GREEN = "\033[32m"
+var YELLOW = "\033[33m"
 
 func main() {
 	// Windows doesn't natively support color in cmd
-	if runtime.GOOS == "windows" {
+	// Also respect the user's preference to disable color (https://no-color.org)
+	if runtime.GOOS == "windows" || os.Getenv("NO_COLOR") != "" {
 		RESET = ""
 		RED = ""
 		GREEN = ""
+		YELLOW = ""
+		evaluator.UseColor = false
 	}
 
 	// If source file path was not passed, then start the REPL
@@ -31,18 +36,37 @@ func main() {
 		return
 	}
 
+	// fmt command rewrites the script with consistent indentation and spacing
+	if os.Args[1] == "fmt" {
+		if len(os.Args) != 3 {
+			fmt.Printf("%sUsage: frolang fmt fro_script_path%s\n", RED, RESET)
+			os.Exit(2)
+		}
+		formatScript(os.Args[2])
+		return
+	}
+
+	// With --check flag, the script is only parsed and not evaluated
+	// Exit status is non-zero if the script has errors, so that it can be used for linting
+	check := os.Args[1] == "--check"
+	if check && len(os.Args) != 3 {
+		fmt.Printf("%sUsage: frolang --check fro_script_path%s\n", RED, RESET)
+		os.Exit(2)
+	}
+
 	// Read source code from the file into a string
 	filePath := os.Args[1]
-	if parts := strings.Split(filePath, "."); strings.ToLower(parts[len(parts)-1]) != "fro" {
-		fmt.Printf("%sSCRIPT ERROR: %s is not a valid FroLang script.\n\tFile extension should be: .fro%s\n", RED, filePath, RESET)
-		return
+	if check {
+		filePath = os.Args[2]
 	}
-	contentBytes, err := os.ReadFile(filePath)
+	sourceCode, err := repl.ReadScript(filePath)
 	if err != nil {
 		fmt.Printf("%sSCRIPT ERROR: %s%s\n", RED, err, RESET)
+		if check {
+			os.Exit(1)
+		}
 		return
 	}
-	sourceCode := string(contentBytes)
 
 	// Parse the program
 	lex := lexer.New(sourceCode)
@@ -51,15 +75,32 @@ func main() {
 
 	// Evaluate the AST if there was no errors. Else show errors
 	if len(par.Errors()) != 0 {
-		for _, message := range par.Errors() {
+		for _, message := range par.ErrorsWithSource(sourceCode) {
 			fmt.Printf("%sPARSE ERROR: %s%s\n", RED, message, RESET)
 		}
+		if check {
+			os.Exit(1)
+		}
+	} else if check {
+		// Warnings of the static analysis doesn't affect the exit status
+		for _, message := range analyzer.Analyze(program) {
+			fmt.Printf("%sWARNING: %s%s\n", YELLOW, message, RESET)
+		}
+		fmt.Printf("%s%s: OK%s\n", GREEN, filePath, RESET)
 	} else {
 		env := object.NewEnvironment()
-		result := evaluator.Eval(program, env)
+		env.SetPath(filePath)
+		warnings := analyzer.Analyze(program)
+		result := evaluator.SafeEval(program, env)
+		evaluator.CloseFiles()
+
+		// Warnings are shown after the output of the script, leaving out what the error reports
+		for _, message := range analyzer.Unreported(warnings, result) {
+			fmt.Printf("%sWARNING: %s%s\n", YELLOW, message, RESET)
+		}
 
-		// Show errors/result if any
-		if result != nil {
+		// Show errors/result if any. Null result, like that of print, is not shown
+		if result != nil && result.Type() != object.NULL_OBJ {
 			if result.Type() == object.ERROR_OBJ {
 				fmt.Printf("%s%s%s\n", RED, result.Inspect(), RESET)
 			} else {
@@ -68,3 +109,27 @@ func main() {
 		}
 	}
 }
+
+// Formats the script and writes it back to the file
+// Exits with non-zero status if the script cannot be read or parsed
+func formatScript(filePath string) {
+	sourceCode, err := repl.ReadScript(filePath)
+	if err != nil {
+		fmt.Printf("%sSCRIPT ERROR: %s%s\n", RED, err, RESET)
+		os.Exit(1)
+	}
+	formatted, errors := formatter.Format(sourceCode)
+	if len(errors) != 0 {
+		for _, message := range errors {
+			fmt.Printf("%sPARSE ERROR: %s%s\n", RED, message, RESET)
+		}
+		os.Exit(1)
+	}
+	if formatted == sourceCode {
+		return
+	}
+	if err := os.WriteFile(filePath, []byte(formatted), 0644); err != nil {
+		fmt.Printf("%sSCRIPT ERROR: %s%s\n", RED, err, RESET)
+		os.Exit(1)
+	}
+}
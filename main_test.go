@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// When FROLANG_MAIN is set, the test binary runs main with the arguments after --, so that its exit status can be checked
+func TestMain(m *testing.M) {
+	if os.Getenv("FROLANG_MAIN") != "" {
+		for index, arg := range os.Args {
+			if arg == "--" {
+				os.Args = append([]string{"frolang"}, os.Args[index+1:]...)
+				break
+			}
+		}
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// Runs main in a separate process and returns its output and exit status
+func runMain(t *testing.T, arguments ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], append([]string{"-test.run=^$", "--"}, arguments...)...)
+	cmd.Env = append(os.Environ(), "FROLANG_MAIN=1", "NO_COLOR=1")
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return string(output), exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output), 0
+}
+
+func writeScript(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckExitStatus(t *testing.T) {
+	valid := writeScript(t, "valid.fro", "let x = 1\nprint(x)\n")
+	invalid := writeScript(t, "invalid.fro", "let = 1\n")
+	tests := []struct {
+		arguments []string
+		status    int
+		output    string
+	}{
+		{[]string{"--check", valid}, 0, valid + ": OK\n"},
+		{[]string{"--check", invalid}, 1, "PARSE ERROR: "},
+		{[]string{"--check", filepath.Join(t.TempDir(), "missing.fro")}, 1, "SCRIPT ERROR: "},
+		{[]string{"--check"}, 2, "Usage: frolang --check fro_script_path\n"},
+	}
+	for _, test := range tests {
+		output, status := runMain(t, test.arguments...)
+		if status != test.status || !strings.HasPrefix(output, test.output) {
+			t.Errorf("%v: expected status %d and output %q, got %d and %q", test.arguments, test.status, test.output, status, output)
+		}
+	}
+}
+
+func TestCheckShowsWarnings(t *testing.T) {
+	unused := writeScript(t, "unused.fro", "let x = 1\n")
+	expected := "WARNING: Unused variable: x at 1:5\n" + unused + ": OK\n"
+	if output, status := runMain(t, "--check", unused); status != 0 || output != expected {
+		t.Errorf("Expected status 0 and output %q, got %d and %q", expected, status, output)
+	}
+}
+
+func TestRunShowsWarnings(t *testing.T) {
+	script := writeScript(t, "script.fro", "let unused = 1\nprint(2)\nprint(missing)\n")
+	expected := "2\nWARNING: Unused variable: unused at 1:5\nEVAL ERROR: Identifier: missing not found at 3:7\n"
+	if output, _ := runMain(t, script); output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+}
+
+func TestFormatCommand(t *testing.T) {
+	script := writeScript(t, "messy.fro", "let   add=fn(a,b){a+b}\n")
+	if output, status := runMain(t, "fmt", script); status != 0 || output != "" {
+		t.Fatalf("Expected formatting to succeed, got %d and %q", status, output)
+	}
+	if formatted, _ := os.ReadFile(script); string(formatted) != "let add = fn(a, b) { a + b };\n" {
+		t.Errorf("Expected the script to be formatted, got %q", formatted)
+	}
+	invalid := writeScript(t, "invalid.fro", "let = 1\n")
+	if _, status := runMain(t, "fmt", invalid); status != 1 {
+		t.Errorf("Expected non-zero status for invalid script, got %d", status)
+	}
+}
@@ -1,12 +1,31 @@
 package object
 
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Environment is safe for concurrent use
+// Each environment guards its own store, so a lookup locks one environment at a time while walking the scope chain
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	mutex     sync.RWMutex
+	store     map[string]Object
+	outer     *Environment
+	path      string
+	imports   []string
+	generator *Generator
+	options   atomic.Value
+}
+
+// Options of an environment, boxed so that the atomic value always holds the same type
+type optionsBox struct {
+	options interface{}
 }
 
 // Adds value to supplied identifier in the environment
 func (environment *Environment) Set(name string, object Object) Object {
+	environment.mutex.Lock()
+	defer environment.mutex.Unlock()
 	environment.store[name] = object
 	return object
 }
@@ -14,25 +33,166 @@ func (environment *Environment) Set(name string, object Object) Object {
 // Updates value of supplied identifier in the environment in which it was declared
 func (environment *Environment) Update(name string, object Object) Object {
 	for env := environment; env != nil; env = env.outer {
-		if _, ok := env.store[name]; ok {
-			env.store[name] = object
+		if env.updateIfDeclared(name, object) {
 			return object
 		}
 	}
-	environment.store[name] = object
-	return object
+	return environment.Set(name, object)
+}
+
+// Updates value of supplied identifier, only if it was declared in this environment
+// Returns whether the value was updated
+func (environment *Environment) updateIfDeclared(name string, object Object) bool {
+	environment.mutex.Lock()
+	defer environment.mutex.Unlock()
+	if _, ok := environment.store[name]; ok {
+		environment.store[name] = object
+		return true
+	}
+	return false
 }
 
 // Retrieves value of supplied identifier from environment
 // If identifier is not present in current environment, look up in outer environment (Scope chain)
 func (environment *Environment) Get(name string) (Object, bool) {
+	environment.mutex.RLock()
 	object, ok := environment.store[name]
+	environment.mutex.RUnlock()
 	if !ok && environment.outer != nil {
 		return environment.outer.Get(name)
 	}
 	return object, ok
 }
 
+// Returns a copy of the name to object mappings declared in this environment
+// Bindings of the outer environments are not included
+func (environment *Environment) Bindings() map[string]Object {
+	environment.mutex.RLock()
+	defer environment.mutex.RUnlock()
+	bindings := make(map[string]Object, len(environment.store))
+	for name, object := range environment.store {
+		bindings[name] = object
+	}
+	return bindings
+}
+
+// Returns the outer environment. Returns nil for the global environment
+func (environment *Environment) Outer() *Environment {
+	return environment.outer
+}
+
+// Bindings of an environment and of its outer environments, captured at a point in time
+type Snapshot struct {
+	layers []snapshotLayer
+}
+
+// Copy of the store of one environment in the scope chain
+type snapshotLayer struct {
+	environment *Environment
+	store       map[string]Object
+}
+
+// Captures the current bindings of the environment and its outer environments
+// Bound objects are not copied. Only the name to object mappings are captured
+func (environment *Environment) Snapshot() *Snapshot {
+	snapshot := &Snapshot{}
+	for env := environment; env != nil; env = env.outer {
+		env.mutex.RLock()
+		store := make(map[string]Object, len(env.store))
+		for name, object := range env.store {
+			store[name] = object
+		}
+		env.mutex.RUnlock()
+		snapshot.layers = append(snapshot.layers, snapshotLayer{environment: env, store: store})
+	}
+	return snapshot
+}
+
+// Rolls back every environment captured in the snapshot to its captured bindings
+// Bindings created after the snapshot are removed and updated bindings get their old value back
+func (environment *Environment) Restore(snapshot *Snapshot) {
+	for _, layer := range snapshot.layers {
+		store := make(map[string]Object, len(layer.store))
+		for name, object := range layer.store {
+			store[name] = object
+		}
+		layer.environment.mutex.Lock()
+		layer.environment.store = store
+		layer.environment.mutex.Unlock()
+	}
+}
+
+// Sets the path of the source file evaluated in this environment
+func (environment *Environment) SetPath(path string) {
+	environment.path = path
+}
+
+// Returns the path of the source file evaluated in this environment
+// If path is not set in current environment, look up in outer environment
+// Returns empty string for code that does not come from a file (REPL)
+func (environment *Environment) Path() string {
+	for env := environment; env != nil; env = env.outer {
+		if env.path != "" {
+			return env.path
+		}
+	}
+	return ""
+}
+
+// Sets the absolute paths of the modules being imported when this environment was created, in import order
+// The last path is the module evaluated in this environment. Used to detect circular imports
+func (environment *Environment) SetImports(paths []string) {
+	environment.imports = paths
+}
+
+// Returns the chain of imports that led to evaluating the code in this environment
+// If imports are not set in current environment, look up in outer environment
+// Returns nil for the entry script and REPL
+func (environment *Environment) Imports() []string {
+	for env := environment; env != nil; env = env.outer {
+		if env.imports != nil {
+			return env.imports
+		}
+	}
+	return nil
+}
+
+// Sets the generator whose function body is evaluated in this environment
+func (environment *Environment) SetGenerator(generator *Generator) {
+	environment.generator = generator
+}
+
+// Returns the generator whose function body is evaluated in this environment
+// If generator is not set in current environment, look up in outer environment
+// Returns nil if the code is not running inside a generator function
+func (environment *Environment) Generator() *Generator {
+	for env := environment; env != nil; env = env.outer {
+		if env.generator != nil {
+			return env.generator
+		}
+	}
+	return nil
+}
+
+// Sets the options of the evaluation run in this environment, like its iteration limit
+// Options are defined by the evaluator. The environment only carries them to the code evaluated in it
+// Options can be replaced while tasks spawned from the environment are running, so they are stored atomically
+func (environment *Environment) SetOptions(options interface{}) {
+	environment.options.Store(optionsBox{options: options})
+}
+
+// Returns the options of the evaluation run in this environment
+// If options are not set in current environment, look up in outer environment
+// Returns nil if no options were set
+func (environment *Environment) Options() interface{} {
+	for env := environment; env != nil; env = env.outer {
+		if box, ok := env.options.Load().(optionsBox); ok && box.options != nil {
+			return box.options
+		}
+	}
+	return nil
+}
+
 // Constructor function for global environment
 // *outer points to null as this is the outermost environment
 func NewEnvironment() *Environment {
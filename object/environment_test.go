@@ -0,0 +1,72 @@
+package object
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Goroutines set, update and get variables of shared environments at the same time. Run with -race
+func TestEnvironmentConcurrentAccess(t *testing.T) {
+	global := NewEnvironment()
+	global.Set("count", &Integer{Value: 0})
+	var wait sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wait.Add(1)
+		go func(worker int) {
+			defer wait.Done()
+			local := NewEnclosedEnvironment(global)
+			for i := 0; i < 200; i++ {
+				name := fmt.Sprintf("w%d", worker)
+				global.Set(name, &Integer{Value: i})
+				local.Set("i", &Integer{Value: i})
+				local.Update("count", &Integer{Value: i})
+				local.Get("count")
+				local.Get(name)
+			}
+		}(worker)
+	}
+	wait.Wait()
+	for worker := 0; worker < 8; worker++ {
+		if value, ok := global.Get(fmt.Sprintf("w%d", worker)); !ok || value.Inspect() != "199" {
+			t.Errorf("Expected w%d to be 199, got %v", worker, value)
+		}
+	}
+}
+
+func TestEnvironmentSnapshot(t *testing.T) {
+	global := NewEnvironment()
+	global.Set("x", &Integer{Value: 1})
+	local := NewEnclosedEnvironment(global)
+	snapshot := local.Snapshot()
+
+	local.Set("added", &Integer{Value: 2})
+	global.Set("global", &Integer{Value: 3})
+	local.Update("x", &Integer{Value: 4})
+	local.Restore(snapshot)
+
+	for _, name := range []string{"added", "global"} {
+		if _, ok := local.Get(name); ok {
+			t.Errorf("Expected %s to vanish on restore", name)
+		}
+	}
+	if value, _ := local.Get("x"); value.Inspect() != "1" {
+		t.Errorf("Expected x to be restored to 1, got %s", value.Inspect())
+	}
+}
+
+func TestEnvironmentPathAndImports(t *testing.T) {
+	global := NewEnvironment()
+	global.SetPath("/scripts/main.fro")
+	local := NewEnclosedEnvironment(NewEnclosedEnvironment(global))
+	if local.Path() != "/scripts/main.fro" {
+		t.Errorf("Expected path of the outer environment, got %q", local.Path())
+	}
+	if local.Imports() != nil {
+		t.Errorf("Expected no imports for the entry script, got %v", local.Imports())
+	}
+	global.SetImports([]string{"/scripts/a.fro"})
+	if imports := local.Imports(); len(imports) != 1 || imports[0] != "/scripts/a.fro" {
+		t.Errorf("Expected imports of the outer environment, got %v", imports)
+	}
+}
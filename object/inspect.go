@@ -0,0 +1,161 @@
+package object
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Maximum nesting of containers rendered by Inspect/Repr
+// Containers nested deeper than this are shown as [...] or {...}
+const maxInspectDepth = 64
+
+// Maximum number of elements/pairs of a container rendered by Inspect/Repr
+// Remaining ones are shown as ... after them. If not positive, all of them are rendered
+var MaxInspectElements = 100
+
+// Maximum number of characters in the string produced by Inspect of containers and Repr
+// Longer strings are cut at the limit and ... is appended. If not positive, the length is not limited
+// Both this and MaxInspectElements apply to the whole process, and are read without synchronization
+// Change them before evaluating code, not while a program or its spawned tasks are running
+var MaxInspectLength = 0
+
+// Returns an unambiguous representation of the object for debugging
+// Unlike Inspect, strings are quoted. So the string "1" can be distinguished from the integer 1
+func Repr(obj Object) string {
+	return truncate(render(obj, true, nil))
+}
+
+// Cuts the string to MaxInspectLength characters and appends ... to it, if it is longer than that
+func truncate(str string) string {
+	if MaxInspectLength <= 0 || utf8.RuneCountInString(str) <= MaxInspectLength {
+		return str
+	}
+	return string([]rune(str)[:MaxInspectLength]) + "..."
+}
+
+// Renders the object as string. Strings are quoted if quote is true
+// parents holds the containers which are being rendered, from outermost to innermost
+// If a container is one of its own parents (self-referential), or too deeply nested,
+// it is rendered as [...] or {...} instead of recursing forever
+func render(obj Object, quote bool, parents []Object) string {
+	switch obj := obj.(type) {
+	case *String:
+		if quote {
+			return strconv.Quote(obj.Value)
+		}
+		return obj.Value
+	case *Array:
+		if isRendering(obj, parents) {
+			return "[...]"
+		}
+		parents = append(parents, obj)
+		return "[" + renderElements(obj.Elements, quote, parents) + "]"
+	case *Tuple:
+		if len(obj.Elements) == 1 {
+			return "(" + render(obj.Elements[0], quote, parents) + ",)"
+		}
+		return "(" + renderElements(obj.Elements, quote, parents) + ")"
+	case *Hash:
+		if isRendering(obj, parents) {
+			return "{...}"
+		}
+		parents = append(parents, obj)
+		// Strings in a hash are always quoted, so that keys like "1" and 1 can be told apart
+		pairs := []string{}
+		for index, pair := range obj.SortedPairs() {
+			if isTruncated(index) {
+				pairs = append(pairs, "...")
+				break
+			}
+			pairs = append(pairs, fmt.Sprintf("%s: %s", render(pair.Key, true, parents), render(pair.Value, true, parents)))
+		}
+		return "{" + strings.Join(pairs, ", ") + "}"
+	default:
+		return obj.Inspect()
+	}
+}
+
+// Renders the elements separated by comma. Elements exceeding MaxInspectElements are shown as ...
+func renderElements(elements []Object, quote bool, parents []Object) string {
+	rendered := []string{}
+	for index, element := range elements {
+		if isTruncated(index) {
+			rendered = append(rendered, "...")
+			break
+		}
+		rendered = append(rendered, render(element, quote, parents))
+	}
+	return strings.Join(rendered, ", ")
+}
+
+// Helper function to check whether the element at index exceeds MaxInspectElements
+func isTruncated(index int) bool {
+	return MaxInspectElements > 0 && index >= MaxInspectElements
+}
+
+// Helper function to check whether the container is already being rendered or is nested too deep
+func isRendering(container Object, parents []Object) bool {
+	if len(parents) >= maxInspectDepth {
+		return true
+	}
+	for _, parent := range parents {
+		if parent == container {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the pairs of the hash sorted by their keys, since the order of the map is random
+// Numbers come first in numeric order, followed by strings, booleans and then the rest
+func (hash *Hash) SortedPairs() []HashPair {
+	pairs := make([]HashPair, 0, len(hash.Pairs))
+	for _, pair := range hash.Pairs {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool { return keyLess(pairs[i].Key, pairs[j].Key) })
+	return pairs
+}
+
+// Helper function to order the hash keys
+func keyLess(left Object, right Object) bool {
+	leftRank, rightRank := keyRank(left), keyRank(right)
+	if leftRank != rightRank {
+		return leftRank < rightRank
+	}
+	switch left := left.(type) {
+	case *Integer:
+		return numberValue(left) < numberValue(right)
+	case *Float:
+		return numberValue(left) < numberValue(right)
+	case *String:
+		return left.Value < right.(*String).Value
+	case *Boolean:
+		return !left.Value && right.(*Boolean).Value
+	}
+	return Repr(left) < Repr(right)
+}
+
+// Rank of the key's type in the order of hash keys
+func keyRank(key Object) int {
+	switch key.(type) {
+	case *Integer, *Float:
+		return 0
+	case *String:
+		return 1
+	case *Boolean:
+		return 2
+	}
+	return 3
+}
+
+// Helper function to get the value of a number as float
+func numberValue(number Object) float64 {
+	if integer, ok := number.(*Integer); ok {
+		return float64(integer.Value)
+	}
+	return number.(*Float).Value
+}
@@ -1,26 +1,38 @@
 package object
 
 import (
+	"bufio"
+	"encoding/binary"
 	"fmt"
 	"hash/fnv"
+	"math"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/mochatek/frolang/ast"
 )
 
 const (
-	INTEGER_OBJ  = "INTEGER"
-	FLOAT_OBJ    = "FLOAT"
-	STRING_OBJ   = "STRING"
-	BOOLEAN_OBJ  = "BOOLEAN"
-	ARRAY_OBJ    = "ARRAY"
-	HASH_OBJ     = "HASH"
-	NULL_OBJ     = "NULL"
-	RETURN_OBJ   = "RETURN_VALUE"
-	FUNCTION_OBJ = "FUNCTION"
-	ERROR_OBJ    = "ERROR"
-	BUILTIN_OBJ  = "BUILTIN"
-	JUMP_OBJ     = "JUMP"
+	INTEGER_OBJ   = "INTEGER"
+	FLOAT_OBJ     = "FLOAT"
+	STRING_OBJ    = "STRING"
+	BOOLEAN_OBJ   = "BOOLEAN"
+	ARRAY_OBJ     = "ARRAY"
+	HASH_OBJ      = "HASH"
+	NULL_OBJ      = "NULL"
+	RETURN_OBJ    = "RETURN_VALUE"
+	FUNCTION_OBJ  = "FUNCTION"
+	ERROR_OBJ     = "ERROR"
+	BUILTIN_OBJ   = "BUILTIN"
+	JUMP_OBJ      = "JUMP"
+	MODULE_OBJ    = "MODULE"
+	CHANNEL_OBJ   = "CHANNEL"
+	TASK_OBJ      = "TASK"
+	FILE_OBJ      = "FILE"
+	GENERATOR_OBJ = "GENERATOR"
+	TUPLE_OBJ     = "TUPLE"
 )
 
 type ObjectType string
@@ -34,6 +46,10 @@ type Iterable interface {
 	Iter() Array
 }
 
+type Closer interface {
+	Close() error
+}
+
 type HashKey struct {
 	Type  ObjectType
 	Value uint64
@@ -43,6 +59,27 @@ type Hashable interface {
 	HashKey() HashKey
 }
 
+// Returns the hash key of the object and whether it can be hashed
+// Arrays and tuples can only be hashed when all of their elements can be hashed
+func HashKeyOf(obj Object) (HashKey, bool) {
+	var elements []Object
+	switch obj := obj.(type) {
+	case *Array:
+		elements = obj.Elements
+	case *Tuple:
+		elements = obj.Elements
+	}
+	for _, element := range elements {
+		if _, ok := HashKeyOf(element); !ok {
+			return HashKey{}, false
+		}
+	}
+	if hashable, ok := obj.(Hashable); ok {
+		return hashable.HashKey(), true
+	}
+	return HashKey{}, false
+}
+
 type Integer struct {
 	Value int
 }
@@ -53,14 +90,28 @@ func (integer *Integer) HashKey() HashKey {
 	return HashKey{Type: integer.Type(), Value: uint64(integer.Value)}
 }
 
+// Number of digits shown after the decimal point of floats. Values of the floats are not rounded
+// If negative, floats are shown with the fewest digits that represent their value
+// It is a process-wide setting which is not safe for concurrent use. So it should be changed only when no code,
+// including spawned tasks, is being evaluated. Tests changing it have to restore it
+var FloatPrecision = 2
+
 type Float struct {
 	Value float64
 }
 
 func (float *Float) Type() ObjectType { return FLOAT_OBJ }
-func (float *Float) Inspect() string  { return fmt.Sprintf("%.2f", float.Value) }
+func (float *Float) Inspect() string {
+	return strconv.FormatFloat(float.Value, 'f', FloatPrecision, 64)
+}
+
+// Float with an integral value has the same hash key as that integer, as they are equal
+// Otherwise, hash key is made from the bits of the float, so that floats like 1.2 and 1.5 do not collide
 func (float *Float) HashKey() HashKey {
-	return HashKey{Type: float.Type(), Value: uint64(float.Value)}
+	if float.Value == math.Trunc(float.Value) && math.Abs(float.Value) < math.MaxInt64 {
+		return HashKey{Type: INTEGER_OBJ, Value: uint64(int(float.Value))}
+	}
+	return HashKey{Type: float.Type(), Value: math.Float64bits(float.Value)}
 }
 
 type Boolean struct {
@@ -103,27 +154,62 @@ type Array struct {
 }
 
 func (array *Array) Type() ObjectType { return ARRAY_OBJ }
-func (array *Array) Inspect() string {
-	var str strings.Builder
-	elements := []string{}
-	for _, element := range array.Elements {
-		elements = append(elements, element.Inspect())
-	}
-	str.WriteString("[")
-	str.WriteString(strings.Join(elements, ", "))
-	str.WriteString("]")
-	return str.String()
-}
+func (array *Array) Inspect() string  { return truncate(render(array, false, nil)) }
 func (array *Array) Iter() Array {
 	return *array
 }
 
+// Hash key is made from the hash keys of the elements in order, so that arrays with equal elements have same key
+// Use HashKeyOf to check whether the elements can be hashed before using the key
+func (array *Array) HashKey() HashKey {
+	return HashKey{Type: array.Type(), Value: hashElements(array.Elements)}
+}
+
+// Tuple is a fixed size, immutable group of elements. ie, (1, "a")
+// Unlike arrays, tuples are compared by their elements
+type Tuple struct {
+	Elements []Object
+}
+
+func (tuple *Tuple) Type() ObjectType { return TUPLE_OBJ }
+func (tuple *Tuple) Inspect() string  { return truncate(render(tuple, false, nil)) }
+func (tuple *Tuple) Iter() Array {
+	return Array{Elements: tuple.Elements}
+}
+func (tuple *Tuple) HashKey() HashKey {
+	return HashKey{Type: tuple.Type(), Value: hashElements(tuple.Elements)}
+}
+
+// Returns a 64-bit hash of the object, which is the same for objects having the same hash key
+// Unlike the hash key, it also depends on the type of the object. Returns false if the object is not hashable
+func HashOf(obj Object) (uint64, bool) {
+	if _, ok := HashKeyOf(obj); !ok {
+		return 0, false
+	}
+	return hashElements([]Object{obj}), true
+}
+
+// Helper function to combine the hash keys of the elements in order
+func hashElements(elements []Object) uint64 {
+	hash := fnv.New64a()
+	buffer := make([]byte, 8)
+	for _, element := range elements {
+		key, _ := HashKeyOf(element)
+		hash.Write([]byte(key.Type))
+		binary.LittleEndian.PutUint64(buffer, key.Value)
+		hash.Write(buffer)
+	}
+	return hash.Sum64()
+}
+
 type Null struct{}
 
 func (null *Null) Type() ObjectType { return NULL_OBJ }
 func (null *Null) Inspect() string  { return "null" }
 
 type Function struct {
+	Name       string
+	Generator  bool
 	Parameters []*ast.Identifier
 	Body       *ast.BlockStatement
 	Env        *Environment
@@ -136,7 +222,11 @@ func (function *Function) Inspect() string {
 	for _, parameter := range function.Parameters {
 		parameters = append(parameters, parameter.String())
 	}
-	str.WriteString("fn(")
+	str.WriteString("fn")
+	if function.Name != "" {
+		str.WriteString(" " + function.Name)
+	}
+	str.WriteString("(")
 	str.WriteString(strings.Join(parameters, ", "))
 	str.WriteString(")")
 	str.WriteString(function.Body.String())
@@ -150,12 +240,21 @@ type ReturnValue struct {
 func (returnValue *ReturnValue) Type() ObjectType { return RETURN_OBJ }
 func (returnValue *ReturnValue) Inspect() string  { return returnValue.Value.Inspect() }
 
+// Stack holds the calls of user functions the error passed through, from the innermost to the outermost
 type Error struct {
 	Message string
+	Stack   []string
 }
 
 func (err *Error) Type() ObjectType { return ERROR_OBJ }
-func (err *Error) Inspect() string  { return "EVAL ERROR: " + err.Message }
+func (err *Error) Inspect() string {
+	var str strings.Builder
+	str.WriteString("EVAL ERROR: " + err.Message)
+	for _, frame := range err.Stack {
+		str.WriteString("\n    in " + frame)
+	}
+	return str.String()
+}
 
 type builtinFunction func(arguments ...Object) Object
 
@@ -176,17 +275,7 @@ type Hash struct {
 }
 
 func (hash *Hash) Type() ObjectType { return HASH_OBJ }
-func (hash *Hash) Inspect() string {
-	var str strings.Builder
-	pairs := []string{}
-	for _, pair := range hash.Pairs {
-		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
-	}
-	str.WriteString("{")
-	str.WriteString(strings.Join(pairs, ", "))
-	str.WriteString("}")
-	return str.String()
-}
+func (hash *Hash) Inspect() string  { return truncate(render(hash, false, nil)) }
 func (hash *Hash) Iter() Array {
 	array := Array{}
 	for _, pair := range hash.Pairs {
@@ -201,3 +290,110 @@ type Jump struct {
 
 func (jump *Jump) Type() ObjectType { return JUMP_OBJ }
 func (jump *Jump) Inspect() string  { return "" }
+
+type Module struct {
+	Name    string
+	Members map[string]Object
+}
+
+func (module *Module) Type() ObjectType { return MODULE_OBJ }
+func (module *Module) Inspect() string  { return "Module " + module.Name }
+
+type Channel struct {
+	Value chan Object
+}
+
+func (channel *Channel) Type() ObjectType { return CHANNEL_OBJ }
+func (channel *Channel) Inspect() string {
+	return fmt.Sprintf("Channel(%d/%d)", len(channel.Value), cap(channel.Value))
+}
+
+type Task struct {
+	Done   chan struct{}
+	Result Object
+}
+
+func (task *Task) Type() ObjectType { return TASK_OBJ }
+func (task *Task) Inspect() string  { return "Task" }
+
+type File struct {
+	Path   string
+	Mode   string
+	Handle *os.File
+	Reader *bufio.Reader
+	Closed bool
+}
+
+func (file *File) Type() ObjectType { return FILE_OBJ }
+func (file *File) Inspect() string {
+	if file.Closed {
+		return fmt.Sprintf("File(%s, closed)", file.Path)
+	}
+	return fmt.Sprintf("File(%s)", file.Path)
+}
+func (file *File) Close() error {
+	if file.Closed {
+		return nil
+	}
+	file.Closed = true
+	return file.Handle.Close()
+}
+
+// Generator produces the values yielded by a generator function, one at a time
+// The function body runs on a separate goroutine, which is paused at each yield until the next value is requested
+// Values carries the yielded values and is closed when the function body finishes
+// Resume tells the paused body to continue (true) or to stop (false)
+type Generator struct {
+	mutex  sync.Mutex
+	Values chan Object
+	Resume chan bool
+	Done   bool
+}
+
+func (generator *Generator) Type() ObjectType { return GENERATOR_OBJ }
+func (generator *Generator) Inspect() string {
+	if generator.Done {
+		return "Generator(done)"
+	}
+	return "Generator"
+}
+
+// Resumes the function body and returns the next yielded value
+// Returns false once the function body has finished
+func (generator *Generator) Next() (Object, bool) {
+	generator.mutex.Lock()
+	defer generator.mutex.Unlock()
+	if generator.Done {
+		return nil, false
+	}
+	generator.Resume <- true
+	value, ok := <-generator.Values
+	if !ok {
+		generator.Done = true
+	}
+	return value, ok
+}
+
+// Stops the paused function body. No more values are produced after closing
+func (generator *Generator) Close() error {
+	generator.mutex.Lock()
+	defer generator.mutex.Unlock()
+	if !generator.Done {
+		generator.Done = true
+		generator.Resume <- false
+	}
+	return nil
+}
+
+// Collects all the remaining values of the generator into an array
+// Never returns for an infinite generator. Use Next to consume it lazily
+func (generator *Generator) Iter() Array {
+	array := Array{}
+	for {
+		value, ok := generator.Next()
+		if !ok {
+			return array
+		}
+		array.Elements = append(array.Elements, value)
+	}
+}
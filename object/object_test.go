@@ -0,0 +1,117 @@
+package object
+
+import "testing"
+
+func TestRepr(t *testing.T) {
+	tests := []struct {
+		obj           Object
+		inspect, repr string
+	}{
+		{&Integer{Value: 1}, "1", "1"},
+		{&String{Value: "1"}, "1", `"1"`},
+		{&Array{Elements: []Object{&Integer{Value: 1}, &String{Value: "a"}}}, "[1, a]", `[1, "a"]`},
+	}
+	for _, test := range tests {
+		if inspect := test.obj.Inspect(); inspect != test.inspect {
+			t.Errorf("Expected inspect %q, got %q", test.inspect, inspect)
+		}
+		if repr := Repr(test.obj); repr != test.repr {
+			t.Errorf("Expected repr %q, got %q", test.repr, repr)
+		}
+	}
+}
+
+func TestInspectSelfReferential(t *testing.T) {
+	array := &Array{}
+	array.Elements = []Object{&Integer{Value: 1}, array}
+	if inspect := array.Inspect(); inspect != "[1, [...]]" {
+		t.Errorf("Expected cycle to be shown as [...], got %q", inspect)
+	}
+	hash := &Hash{Pairs: map[HashKey]HashPair{}}
+	key := &Integer{Value: 1}
+	hash.Pairs[key.HashKey()] = HashPair{Key: key, Value: hash}
+	if inspect := hash.Inspect(); inspect != "{1: {...}}" {
+		t.Errorf("Expected cycle to be shown as {...}, got %q", inspect)
+	}
+}
+
+func TestHashKeyOf(t *testing.T) {
+	pair := func(elements ...Object) []Object { return elements }
+	tests := []struct {
+		left, right Object
+		equal       bool
+	}{
+		{&Array{Elements: pair(&Integer{Value: 1}, &Integer{Value: 2})}, &Array{Elements: pair(&Integer{Value: 1}, &Integer{Value: 2})}, true},
+		{&Array{Elements: pair(&Integer{Value: 1}, &Integer{Value: 2})}, &Array{Elements: pair(&Integer{Value: 2}, &Integer{Value: 1})}, false},
+		{&Array{Elements: pair(&Integer{Value: 1})}, &Array{Elements: pair(&Integer{Value: 1}, &Integer{Value: 1})}, false},
+		{&Integer{Value: 2}, &Float{Value: 2}, true},
+		{&Float{Value: 1.2}, &Float{Value: 1.5}, false},
+	}
+	for _, test := range tests {
+		left, leftOk := HashKeyOf(test.left)
+		right, rightOk := HashKeyOf(test.right)
+		if !leftOk || !rightOk {
+			t.Errorf("Expected %s and %s to be hashable", test.left.Inspect(), test.right.Inspect())
+		} else if (left == right) != test.equal {
+			t.Errorf("%s and %s: expected equal keys to be %t", test.left.Inspect(), test.right.Inspect(), test.equal)
+		}
+	}
+	if _, ok := HashKeyOf(&Array{Elements: pair(&Hash{Pairs: map[HashKey]HashPair{}})}); ok {
+		t.Errorf("Expected array of hash not to be hashable")
+	}
+}
+
+func TestHashInspect(t *testing.T) {
+	hash := &Hash{Pairs: map[HashKey]HashPair{}}
+	for _, key := range []Object{&String{Value: "b"}, &Integer{Value: 2}, &String{Value: "a"}, &Boolean{Value: true}} {
+		hash.Pairs[key.(Hashable).HashKey()] = HashPair{Key: key, Value: &String{Value: "x"}}
+	}
+	if inspect := hash.Inspect(); inspect != `{2: "x", "a": "x", "b": "x", true: "x"}` {
+		t.Errorf("Expected sorted pairs with quoted strings, got %q", inspect)
+	}
+}
+
+// Order of the pairs doesn't depend on the iteration order of the map
+func TestHashInspectIsStable(t *testing.T) {
+	hash := &Hash{Pairs: map[HashKey]HashPair{}}
+	for i := 0; i < 20; i++ {
+		key := &Integer{Value: i}
+		hash.Pairs[key.HashKey()] = HashPair{Key: key, Value: key}
+	}
+	expected := hash.Inspect()
+	for i := 0; i < 10; i++ {
+		if inspect := hash.Inspect(); inspect != expected {
+			t.Fatalf("Expected %q, got %q", expected, inspect)
+		}
+	}
+}
+
+func TestFloatPrecision(t *testing.T) {
+	defer func(precision int) { FloatPrecision = precision }(FloatPrecision)
+	float := &Float{Value: 0.1 + 0.2}
+	for precision, expected := range map[int]string{2: "0.30", 0: "0", 5: "0.30000"} {
+		FloatPrecision = precision
+		if inspect := float.Inspect(); inspect != expected {
+			t.Errorf("Precision %d: expected %q, got %q", precision, expected, inspect)
+		}
+	}
+	if float.Value != 0.1+0.2 {
+		t.Errorf("Expected the value to keep its precision, got %v", float.Value)
+	}
+}
+
+func TestInspectLimits(t *testing.T) {
+	defer func(length int) { MaxInspectLength = length }(MaxInspectLength)
+	array := &Array{}
+	for i := 0; i < 10; i++ {
+		array.Elements = append(array.Elements, &Integer{Value: i})
+	}
+	MaxInspectLength = 8
+	if inspect := array.Inspect(); inspect != "[0, 1, 2..." {
+		t.Errorf("Expected truncated array, got %q", inspect)
+	}
+	MaxInspectLength = 0
+	if inspect := array.Inspect(); inspect != "[0, 1, 2, 3, 4, 5, 6, 7, 8, 9]" {
+		t.Errorf("Expected whole array, got %q", inspect)
+	}
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/mochatek/frolang/ast"
 	"github.com/mochatek/frolang/lexer"
@@ -21,12 +22,16 @@ type Parser struct {
 	prefixParsers map[token.TokenType]prefixParser
 	infixParsers  map[token.TokenType]infixParser
 	errors        []string
+	locations     []string
+	functions     []*ast.FunctionLiteral
 }
 
 // Precedence scores
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN
+	COALESCE
 	EQUALS
 	LESS_GREATER
 	SUM
@@ -38,7 +43,8 @@ const (
 
 // Operator precedence
 var precedenceMap = map[token.TokenType]int{
-	token.ASSIGN:    EQUALS,
+	token.ASSIGN:    ASSIGN,
+	token.COALESCE:  COALESCE,
 	token.EQ:        EQUALS,
 	token.NOT_EQ:    EQUALS,
 	token.AND:       EQUALS,
@@ -54,6 +60,11 @@ var precedenceMap = map[token.TokenType]int{
 	token.SLASH:     PRODUCT,
 	token.L_PAREN:   CALL,
 	token.L_BRACKET: INDEX,
+	token.DOT:       INDEX,
+	token.Q_BRACKET: INDEX,
+	token.Q_DOT:     INDEX,
+	token.INCREMENT: INDEX,
+	token.DECREMENT: INDEX,
 }
 
 // Constructor function for parser
@@ -80,6 +91,7 @@ func New(lexer *lexer.Lexer) *Parser {
 	parser.registerPrefixParser(token.BANG, parser.parsePrefixExpression)
 	parser.registerPrefixParser(token.L_PAREN, parser.parseGroupedExpression)
 	parser.registerPrefixParser(token.IF, parser.parseIfExpression)
+	parser.registerPrefixParser(token.MATCH, parser.parseMatchExpression)
 
 	parser.registerInfixParser(token.PLUS, parser.parseInfixExpression)
 	parser.registerInfixParser(token.MINUS, parser.parseInfixExpression)
@@ -87,16 +99,22 @@ func New(lexer *lexer.Lexer) *Parser {
 	parser.registerInfixParser(token.SLASH, parser.parseInfixExpression)
 	parser.registerInfixParser(token.EQ, parser.parseInfixExpression)
 	parser.registerInfixParser(token.NOT_EQ, parser.parseInfixExpression)
-	parser.registerInfixParser(token.LT, parser.parseInfixExpression)
-	parser.registerInfixParser(token.LT_EQ, parser.parseInfixExpression)
-	parser.registerInfixParser(token.GT, parser.parseInfixExpression)
-	parser.registerInfixParser(token.GT_EQ, parser.parseInfixExpression)
+	parser.registerInfixParser(token.LT, parser.parseComparisonExpression)
+	parser.registerInfixParser(token.LT_EQ, parser.parseComparisonExpression)
+	parser.registerInfixParser(token.GT, parser.parseComparisonExpression)
+	parser.registerInfixParser(token.GT_EQ, parser.parseComparisonExpression)
 	parser.registerInfixParser(token.AND, parser.parseInfixExpression)
 	parser.registerInfixParser(token.OR, parser.parseInfixExpression)
+	parser.registerInfixParser(token.COALESCE, parser.parseInfixExpression)
 	parser.registerInfixParser(token.IN, parser.parseInfixExpression)
 	parser.registerInfixParser(token.L_PAREN, parser.parseCallExpression)
 	parser.registerInfixParser(token.L_BRACKET, parser.parseIndexExpression)
+	parser.registerInfixParser(token.DOT, parser.parseMemberExpression)
+	parser.registerInfixParser(token.Q_BRACKET, parser.parseIndexExpression)
+	parser.registerInfixParser(token.Q_DOT, parser.parseMemberExpression)
 	parser.registerInfixParser(token.ASSIGN, parser.parseAssignExpression)
+	parser.registerInfixParser(token.INCREMENT, parser.parsePostfixExpression)
+	parser.registerInfixParser(token.DECREMENT, parser.parsePostfixExpression)
 
 	return parser
 }
@@ -129,6 +147,15 @@ func (parser *Parser) curPrecedence() int {
 	return LOWEST
 }
 
+// Returns the precedence score of an infix operator, or LOWEST if it is not one
+// Useful for printing expressions with only the necessary parentheses
+func Precedence(operator string) int {
+	if precedence, ok := precedenceMap[token.TokenType(operator)]; ok {
+		return precedence
+	}
+	return LOWEST
+}
+
 // Returns the precedence score of peek token
 func (parser *Parser) peekPrecedence() int {
 	if precedence, ok := precedenceMap[parser.peekToken.Type]; ok {
@@ -160,10 +187,54 @@ func (parser *Parser) Errors() []string {
 	return parser.errors
 }
 
+// Add error message to error list along with the location of the offending token
+func (parser *Parser) addError(location string, message string) {
+	parser.errors = append(parser.errors, message)
+	parser.locations = append(parser.locations, location)
+}
+
+// Returns list of errors discovered while parsing, along with the source context
+// For every error with a known location, the offending line of source is shown
+// with a caret under the column where the error occurred
+func (parser *Parser) ErrorsWithSource(source string) []string {
+	lines := strings.Split(source, "\n")
+	messages := make([]string, len(parser.errors))
+	for index, message := range parser.errors {
+		messages[index] = message
+		var line, col int
+		if _, err := fmt.Sscanf(parser.locations[index], "%d:%d", &line, &col); err != nil {
+			continue
+		}
+		if line < 1 || line > len(lines) {
+			continue
+		}
+		sourceLine := strings.TrimRight(lines[line-1], "\r")
+		if col < 1 || col > len(sourceLine)+1 {
+			continue
+		}
+		// Keep tabs in the padding, so that the caret lines up with the source line
+		padding := strings.Map(func(char rune) rune {
+			if char == '\t' {
+				return char
+			}
+			return ' '
+		}, sourceLine[:col-1])
+		messages[index] = fmt.Sprintf("%s\n    %s\n    %s^", message, sourceLine, padding)
+	}
+	return messages
+}
+
 // Create and add peek error to error list
+// Sets the span of the node from its first token to the current token, which is its last token
+func (parser *Parser) setSpan(node ast.Node, first token.Token) {
+	if spanned, ok := node.(interface{ SetSpan(ast.Span) }); ok {
+		spanned.SetSpan(ast.NewSpan(first, parser.curToken))
+	}
+}
+
 func (parser *Parser) peekError(expectedType token.TokenType) {
 	message := fmt.Sprintf("Expected next token to be %s, got %s instead at %s", expectedType, parser.peekToken.Type, parser.peekToken.Location)
-	parser.errors = append(parser.errors, message)
+	parser.addError(parser.peekToken.Location, message)
 }
 
 // PROGRAM => STATEMENT[]
@@ -175,8 +246,10 @@ func (parser *Parser) ParseProgram() *ast.Program {
 	program := &ast.Program{}
 	program.Statements = []ast.Statement{}
 	for parser.curToken.Type != token.EOF {
+		first := parser.curToken
 		statement := parser.parseStatement()
 		if statement != nil {
+			parser.setSpan(statement, first)
 			program.Statements = append(program.Statements, statement)
 		}
 		parser.scanToken()
@@ -184,29 +257,82 @@ func (parser *Parser) ParseProgram() *ast.Program {
 	return program
 }
 
-// STATEMENT => COMMENT / LET / RETURN / FOR / WHILE / BREAK / CONTINUE / EXPRESSION
+// Parses the whole input as a single expression, without wrapping it in a program
+// An optional ; can follow the expression. Any other token after it is reported as error
+// Returns the expression (nil if parsing failed) along with the errors
+func (parser *Parser) ParseExpression() (ast.Expression, []string) {
+	expression := parser.parseExpression(LOWEST)
+	if expression == nil {
+		return nil, parser.errors
+	}
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.scanToken()
+	}
+	if !parser.peekTokenIs(token.EOF) {
+		message := fmt.Sprintf("Unexpected %s after expression at %s", parser.peekToken.Literal, parser.peekToken.Location)
+		parser.addError(parser.peekToken.Location, message)
+		return nil, parser.errors
+	}
+	return expression, parser.errors
+}
+
+// STATEMENT => COMMENT / LET / RETURN / YIELD / FOR / WHILE / BREAK / CONTINUE / TRY / IMPORT / EXPORT / DEFER / WITH / EXPRESSION
 // Applies parse function to the statement based on current token's type
+// If parsing of the statement failed, then nil is returned instead of a nil pointer of the statement type
 func (parser *Parser) parseStatement() ast.Statement {
 	switch parser.curToken.Type {
 	case token.O_COMMENT:
 		return parser.parseComment()
 	case token.LET:
-		return parser.parseLetStatement()
+		if statement := parser.parseLetStatement(); statement != nil {
+			return statement
+		}
 	case token.RETURN:
-		return parser.parseReturnStatement()
+		if statement := parser.parseReturnStatement(); statement != nil {
+			return statement
+		}
+	case token.YIELD:
+		if statement := parser.parseYieldStatement(); statement != nil {
+			return statement
+		}
 	case token.FOR:
-		return parser.parseForStatement()
+		if statement := parser.parseForStatement(); statement != nil {
+			return statement
+		}
 	case token.WHILE:
-		return parser.parseWhileStatement()
+		if statement := parser.parseWhileStatement(); statement != nil {
+			return statement
+		}
 	case token.BREAK:
 		return parser.parseBreakStatement()
 	case token.CONTINUE:
 		return parser.parseContinueStatement()
 	case token.TRY:
-		return parser.parseTryStatement()
+		if statement := parser.parseTryStatement(); statement != nil {
+			return statement
+		}
+	case token.IMPORT:
+		if statement := parser.parseImportStatement(); statement != nil {
+			return statement
+		}
+	case token.EXPORT:
+		if statement := parser.parseExportStatement(); statement != nil {
+			return statement
+		}
+	case token.DEFER:
+		if statement := parser.parseDeferStatement(); statement != nil {
+			return statement
+		}
+	case token.WITH:
+		if statement := parser.parseWithStatement(); statement != nil {
+			return statement
+		}
 	default:
-		return parser.parseExpressionStatement()
+		if statement := parser.parseExpressionStatement(); statement != nil {
+			return statement
+		}
 	}
+	return nil
 }
 
 // /* COMMENT */
@@ -225,12 +351,19 @@ func (parser *Parser) parseLetStatement() *ast.LetStatement {
 	if !parser.expectPeek(token.IDENTIFIER) {
 		return nil
 	}
-	letStatement.Name = &ast.Identifier{Token: parser.curToken, Value: parser.curToken.Literal}
+	letStatement.Name = parser.newIdentifier()
 	if !parser.expectPeek(token.ASSIGN) {
 		return nil
 	}
 	parser.scanToken()
 	letStatement.Value = parser.parseExpression(LOWEST)
+	if letStatement.Value == nil {
+		return nil
+	}
+	// Function declared with let takes the name of the variable
+	if functionLiteral, ok := letStatement.Value.(*ast.FunctionLiteral); ok {
+		functionLiteral.Name = letStatement.Name.Value
+	}
 	if parser.peekTokenIs(token.SEMICOLON) {
 		parser.scanToken()
 	}
@@ -238,23 +371,58 @@ func (parser *Parser) parseLetStatement() *ast.LetStatement {
 }
 
 // RETURN EXPRESSION
+// Value can be omitted if return is followed by ;, } or end of input
 // Example: return 0
 func (parser *Parser) parseReturnStatement() *ast.ReturnStatement {
 	returnStatement := &ast.ReturnStatement{Token: parser.curToken}
+	if parser.peekTokenIs(token.SEMICOLON) || parser.peekTokenIs(token.R_BRACE) || parser.peekTokenIs(token.EOF) {
+		if parser.peekTokenIs(token.SEMICOLON) {
+			parser.scanToken()
+		}
+		return returnStatement
+	}
 	parser.scanToken()
 	returnStatement.ReturnValue = parser.parseExpression(LOWEST)
+	if returnStatement.ReturnValue == nil {
+		return nil
+	}
 	if parser.peekTokenIs(token.SEMICOLON) {
 		parser.scanToken()
 	}
 	return returnStatement
 }
 
+// YIELD EXPRESSION
+// A function which has a yield statement in its body is a generator function
+// Example: yield count
+func (parser *Parser) parseYieldStatement() *ast.YieldStatement {
+	yieldStatement := &ast.YieldStatement{Token: parser.curToken}
+	if len(parser.functions) == 0 {
+		message := fmt.Sprintf("yield statement can only be used inside function at %s", parser.curToken.Location)
+		parser.addError(parser.curToken.Location, message)
+		return nil
+	}
+	parser.functions[len(parser.functions)-1].Generator = true
+	parser.scanToken()
+	yieldStatement.Value = parser.parseExpression(LOWEST)
+	if yieldStatement.Value == nil {
+		return nil
+	}
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.scanToken()
+	}
+	return yieldStatement
+}
+
 // EXPRESSION
 // In FroLang, every expression is represented as an expression statement
 // The Expression field contains the actual expression
 func (parser *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	expressionStatement := &ast.ExpressionStatement{Token: parser.curToken}
 	expressionStatement.Expression = parser.parseExpression(LOWEST)
+	if expressionStatement.Expression == nil {
+		return nil
+	}
 	if parser.peekTokenIs(token.SEMICOLON) {
 		parser.scanToken()
 	}
@@ -269,22 +437,28 @@ func (parser *Parser) parseBlockStatement() *ast.BlockStatement {
 	blockStatement.Statements = []ast.Statement{}
 	parser.scanToken()
 	for !parser.curTokenIs(token.R_BRACE) && !parser.curTokenIs(token.EOF) {
+		first := parser.curToken
 		statement := parser.parseStatement()
 		if statement != nil {
+			parser.setSpan(statement, first)
 			blockStatement.Statements = append(blockStatement.Statements, statement)
 		}
 		parser.scanToken()
 	}
 	if !parser.curTokenIs(token.R_BRACE) {
+		message := fmt.Sprintf("Expected block to be closed with }, got %s instead at %s", parser.curToken.Type, parser.curToken.Location)
+		parser.addError(parser.curToken.Location, message)
 		return nil
 	} else {
+		parser.setSpan(blockStatement, blockStatement.Token)
 		return blockStatement
 	}
 }
 
-// FOR ELEMENT IN ITERABLE { BODY }
+// FOR ELEMENT IN ITERABLE <IF GUARD> { BODY }
 // Parentheses around loop expression is optional
-// Example: for num in [1, 2, 3] { print(num) }
+// Guard is optional. Elements for which the guard is falsy are skipped
+// Example: for num in [1, 2, 3] { print(num) }, for num in [1, -2, 3] if num > 0 { print(num) }
 func (parser *Parser) parseForStatement() *ast.ForStatement {
 	forStatement := &ast.ForStatement{Token: parser.curToken}
 	hashParentheses := false
@@ -295,12 +469,23 @@ func (parser *Parser) parseForStatement() *ast.ForStatement {
 	if !parser.expectPeek(token.IDENTIFIER) {
 		return nil
 	}
-	forStatement.Element = &ast.Identifier{Token: parser.curToken, Value: parser.curToken.Literal}
+	forStatement.Element = parser.newIdentifier()
 	if !parser.expectPeek(token.IN) {
 		return nil
 	}
 	parser.scanToken()
 	forStatement.Iterator = parser.parseExpression(LOWEST)
+	if forStatement.Iterator == nil {
+		return nil
+	}
+	if parser.peekTokenIs(token.IF) {
+		parser.scanToken()
+		parser.scanToken()
+		forStatement.Guard = parser.parseExpression(LOWEST)
+		if forStatement.Guard == nil {
+			return nil
+		}
+	}
 	if hashParentheses && !parser.expectPeek(token.R_PAREN) {
 		return nil
 	}
@@ -308,28 +493,30 @@ func (parser *Parser) parseForStatement() *ast.ForStatement {
 		return nil
 	}
 	forStatement.Body = parser.parseBlockStatement()
+	if forStatement.Body == nil {
+		return nil
+	}
 	return forStatement
 }
 
 // WHILE CONDITION { BODY }
-// Parentheses around condition is optional
+// Parentheses around condition is optional. It is parsed as a grouped expression
+// So parentheses can also be around a part of the condition. ie, while (n = n * 2) < 100 { .. }
 // Example: while num < 5 { print(num); num = num + 1 }
 func (parser *Parser) parseWhileStatement() *ast.WhileStatement {
 	whileStatement := &ast.WhileStatement{Token: parser.curToken}
-	hashParentheses := false
-	if parser.peekTokenIs(token.L_PAREN) {
-		hashParentheses = true
-		parser.scanToken()
-	}
 	parser.scanToken()
 	whileStatement.Condition = parser.parseExpression(LOWEST)
-	if hashParentheses && !parser.expectPeek(token.R_PAREN) {
+	if whileStatement.Condition == nil {
 		return nil
 	}
 	if !parser.expectPeek(token.L_BRACE) {
 		return nil
 	}
 	whileStatement.Body = parser.parseBlockStatement()
+	if whileStatement.Body == nil {
+		return nil
+	}
 	return whileStatement
 }
 
@@ -363,6 +550,9 @@ func (parser *Parser) parseTryStatement() *ast.TryStatement {
 		return nil
 	}
 	tryStatement.Try = parser.parseBlockStatement()
+	if tryStatement.Try == nil {
+		return nil
+	}
 	if !parser.expectPeek(token.CATCH) {
 		return nil
 	}
@@ -374,7 +564,7 @@ func (parser *Parser) parseTryStatement() *ast.TryStatement {
 	if !parser.expectPeek(token.IDENTIFIER) {
 		return nil
 	}
-	tryStatement.Error = &ast.Identifier{Token: parser.curToken, Value: parser.curToken.Literal}
+	tryStatement.Error = parser.newIdentifier()
 	if hashParentheses && !parser.expectPeek(token.R_PAREN) {
 		return nil
 	}
@@ -382,29 +572,122 @@ func (parser *Parser) parseTryStatement() *ast.TryStatement {
 		return nil
 	}
 	tryStatement.Catch = parser.parseBlockStatement()
+	if tryStatement.Catch == nil {
+		return nil
+	}
 	if parser.peekTokenIs(token.FINALLY) {
 		parser.scanToken()
 		if !parser.expectPeek(token.L_BRACE) {
 			return nil
 		}
 		tryStatement.Finally = parser.parseBlockStatement()
+		if tryStatement.Finally == nil {
+			return nil
+		}
 	}
 	return tryStatement
 }
 
+// IMPORT "PATH" <AS ALIAS>
+// Path is relative to the importing file
+// Alias part is optional
+// Example: import "utils.fro" as utils
+func (parser *Parser) parseImportStatement() *ast.ImportStatement {
+	importStatement := &ast.ImportStatement{Token: parser.curToken}
+	if !parser.expectPeek(token.STRING) {
+		return nil
+	}
+	importStatement.Path = &ast.StringLiteral{Token: parser.curToken, Value: parser.curToken.Literal}
+	parser.setSpan(importStatement.Path, parser.curToken)
+	if parser.peekTokenIs(token.AS) {
+		parser.scanToken()
+		if !parser.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+		importStatement.Alias = parser.newIdentifier()
+	}
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.scanToken()
+	}
+	return importStatement
+}
+
+// EXPORT LET IDENTIFIER = EXPRESSION
+// Only exported variables of a module are visible to the importers
+// Example: export let add = fn(a, b) { a + b }
+func (parser *Parser) parseExportStatement() *ast.ExportStatement {
+	exportStatement := &ast.ExportStatement{Token: parser.curToken}
+	if !parser.expectPeek(token.LET) {
+		return nil
+	}
+	exportStatement.Statement = parser.parseLetStatement()
+	if exportStatement.Statement == nil {
+		return nil
+	}
+	return exportStatement
+}
+
+// DEFER EXPRESSION
+// Expression is evaluated when the enclosing block exits
+// Example: defer print("done")
+func (parser *Parser) parseDeferStatement() *ast.DeferStatement {
+	deferStatement := &ast.DeferStatement{Token: parser.curToken}
+	parser.scanToken()
+	deferStatement.Expression = parser.parseExpression(LOWEST)
+	if deferStatement.Expression == nil {
+		return nil
+	}
+	if parser.peekTokenIs(token.SEMICOLON) {
+		parser.scanToken()
+	}
+	return deferStatement
+}
+
+// WITH RESOURCE AS IDENTIFIER { BODY }
+// Resource is closed when the body exits
+// Example: with open("notes.txt") as file { print(file) }
+func (parser *Parser) parseWithStatement() *ast.WithStatement {
+	withStatement := &ast.WithStatement{Token: parser.curToken}
+	parser.scanToken()
+	withStatement.Resource = parser.parseExpression(LOWEST)
+	if withStatement.Resource == nil {
+		return nil
+	}
+	if !parser.expectPeek(token.AS) {
+		return nil
+	}
+	if !parser.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	withStatement.Name = parser.newIdentifier()
+	if !parser.expectPeek(token.L_BRACE) {
+		return nil
+	}
+	withStatement.Body = parser.parseBlockStatement()
+	if withStatement.Body == nil {
+		return nil
+	}
+	return withStatement
+}
+
 // EXPRESSION
 // Parses an expression using Pratt Parsing
 func (parser *Parser) parseExpression(precedence int) ast.Expression {
+	first := parser.curToken
 	prefix := parser.prefixParsers[parser.curToken.Type]
 	if prefix == nil {
 		message := fmt.Sprintf("No prefix parse function registered for %s at %s", parser.curToken.Type, parser.curToken.Location)
 		if parser.curToken.Type == token.ILLEGAL {
 			message = fmt.Sprintf("Illegal token: %s at %s", parser.curToken.Literal, parser.curToken.Location)
 		}
-		parser.errors = append(parser.errors, message)
+		parser.addError(parser.curToken.Location, message)
 		return nil
 	}
 	leftExpression := prefix()
+	if leftExpression == nil {
+		return nil
+	}
+	parser.setSpan(leftExpression, first)
 
 	for !parser.peekTokenIs(token.SEMICOLON) && parser.peekPrecedence() > precedence {
 		infix := parser.infixParsers[parser.peekToken.Type]
@@ -413,6 +696,10 @@ func (parser *Parser) parseExpression(precedence int) ast.Expression {
 		}
 		parser.scanToken()
 		leftExpression = infix(leftExpression)
+		if leftExpression == nil {
+			return nil
+		}
+		parser.setSpan(leftExpression, first)
 	}
 	return leftExpression
 }
@@ -423,6 +710,9 @@ func (parser *Parser) parsePrefixExpression() ast.Expression {
 	prefixExpression := &ast.PrefixExpression{Token: parser.curToken, Operator: parser.curToken.Literal}
 	parser.scanToken()
 	prefixExpression.Right = parser.parseExpression(PREFIX)
+	if prefixExpression.Right == nil {
+		return nil
+	}
 	return prefixExpression
 }
 
@@ -433,75 +723,212 @@ func (parser *Parser) parseInfixExpression(leftExpression ast.Expression) ast.Ex
 	precedence := parser.curPrecedence()
 	parser.scanToken()
 	infixExpression.Right = parser.parseExpression(precedence)
+	if infixExpression.Right == nil {
+		return nil
+	}
 	return infixExpression
 }
 
+// COMPARISON_EXPRESSION => OPERAND OPERATOR OPERAND <OPERATOR OPERAND ...>
+// Relational operators (<, <=, >, >=) can be chained, where a < b < c means a < b & b < c
+// A single comparison is parsed as an infix expression
+// Example: 1 < x, 1 < x <= 10
+func (parser *Parser) parseComparisonExpression(leftExpression ast.Expression) ast.Expression {
+	comparisonExpression := &ast.ComparisonExpression{Token: parser.curToken, Operands: []ast.Expression{leftExpression}}
+	for {
+		comparisonExpression.Operators = append(comparisonExpression.Operators, parser.curToken.Literal)
+		parser.scanToken()
+		operand := parser.parseExpression(LESS_GREATER)
+		if operand == nil {
+			return nil
+		}
+		comparisonExpression.Operands = append(comparisonExpression.Operands, operand)
+		if !isRelational(parser.peekToken.Type) {
+			break
+		}
+		parser.scanToken()
+	}
+	if len(comparisonExpression.Operators) == 1 {
+		return &ast.InfixExpression{
+			Token:    comparisonExpression.Token,
+			Left:     leftExpression,
+			Operator: comparisonExpression.Operators[0],
+			Right:    comparisonExpression.Operands[1],
+		}
+	}
+	return comparisonExpression
+}
+
+// Helper function to check whether the token is a relational operator
+func isRelational(tokenType token.TokenType) bool {
+	return tokenType == token.LT || tokenType == token.LT_EQ || tokenType == token.GT || tokenType == token.GT_EQ
+}
+
 // GROUPED_EXPRESSION => ( EXPRESSION )
 // A grouped expression is an expression enclosed within parentheses
 // Grouped expression will have higher precedence as per our precedence map
 // Example: (1 + 2) * 3
 func (parser *Parser) parseGroupedExpression() ast.Expression {
+	paren := parser.curToken
 	parser.scanToken()
 	groupedExpression := parser.parseExpression(LOWEST)
+	if groupedExpression == nil {
+		return nil
+	}
+	if parser.peekTokenIs(token.COMMA) {
+		return parser.parseTupleLiteral(paren, groupedExpression)
+	}
 	if !parser.expectPeek(token.R_PAREN) {
 		return nil
 	}
 	return groupedExpression
 }
 
+// TUPLE_LITERAL => ( EXPRESSION, EXPRESSION, .. )
+// A grouped expression followed by comma is a tuple. Single element tuple needs a trailing comma
+// Example: (1, "a"), (1,)
+func (parser *Parser) parseTupleLiteral(paren token.Token, first ast.Expression) ast.Expression {
+	tupleLiteral := &ast.TupleLiteral{Token: paren, Elements: []ast.Expression{first}}
+	for parser.peekTokenIs(token.COMMA) {
+		parser.scanToken()
+		if parser.peekTokenIs(token.R_PAREN) {
+			break
+		}
+		parser.scanToken()
+		element := parser.parseExpression(LOWEST)
+		if element == nil {
+			return nil
+		}
+		tupleLiteral.Elements = append(tupleLiteral.Elements, element)
+	}
+	if !parser.expectPeek(token.R_PAREN) {
+		return nil
+	}
+	return tupleLiteral
+}
+
 // CALL_EXPRESSION => EXPRESSION( ARGUMENT, ARGUMENT, .. )
 // Example: print(1, !true)
 func (parser *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	callExpression := &ast.CallExpression{Token: parser.curToken, Function: function}
 	callExpression.Arguments = parser.parseExpressionList(token.R_PAREN)
+	if callExpression.Arguments == nil {
+		return nil
+	}
 	return callExpression
 }
 
 // IF CONDITION { CONSEQUENCE } <ELSE { ALTERNATE }>
-// Parentheses around condition and Else part is optional
+// Parentheses around condition and Else part is optional. Condition in parentheses is parsed as a grouped expression
 // Example: if age >= 18 { "Adult" } else { "Minor" }
 func (parser *Parser) parseIfExpression() ast.Expression {
 	ifExpression := &ast.IfExpression{Token: parser.curToken}
-	hashParentheses := false
-	if parser.peekTokenIs(token.L_PAREN) {
-		hashParentheses = true
-		parser.scanToken()
-	}
 	parser.scanToken()
 	ifExpression.Condition = parser.parseExpression(LOWEST)
-	if hashParentheses && !parser.expectPeek(token.R_PAREN) {
+	if ifExpression.Condition == nil {
 		return nil
 	}
 	if !parser.expectPeek(token.L_BRACE) {
 		return nil
 	}
 	ifExpression.Consequence = parser.parseBlockStatement()
+	if ifExpression.Consequence == nil {
+		return nil
+	}
 	if parser.peekTokenIs(token.ELSE) {
 		parser.scanToken()
 		if !parser.expectPeek(token.L_BRACE) {
 			return nil
 		}
 		ifExpression.Alternate = parser.parseBlockStatement()
+		if ifExpression.Alternate == nil {
+			return nil
+		}
 	}
 	return ifExpression
 }
 
+// MATCH SUBJECT { PATTERN <IF GUARD> => BODY, PATTERN <IF GUARD> => BODY, ... }
+// Pattern is an expression, which is interpreted as a pattern while evaluating
+// Guard is optional. An arm is chosen only if its guard is truthy as well
+// Body is either a block or an expression
+// Example: match point { [x, 0] => x, [0, y] if y > 0 => { y }, _ => 0 }
+func (parser *Parser) parseMatchExpression() ast.Expression {
+	matchExpression := &ast.MatchExpression{Token: parser.curToken}
+	parser.scanToken()
+	matchExpression.Subject = parser.parseExpression(LOWEST)
+	if matchExpression.Subject == nil || !parser.expectPeek(token.L_BRACE) {
+		return nil
+	}
+	for !parser.peekTokenIs(token.R_BRACE) {
+		parser.scanToken()
+		arm := &ast.MatchArm{Pattern: parser.parseExpression(LOWEST)}
+		if arm.Pattern == nil {
+			return nil
+		}
+		if parser.peekTokenIs(token.IF) {
+			parser.scanToken()
+			parser.scanToken()
+			arm.Guard = parser.parseExpression(LOWEST)
+			if arm.Guard == nil {
+				return nil
+			}
+		}
+		if !parser.expectPeek(token.ARROW) {
+			return nil
+		}
+		parser.scanToken()
+		if parser.curTokenIs(token.L_BRACE) {
+			arm.Body = parser.parseBlockStatement()
+			if arm.Body == nil {
+				return nil
+			}
+		} else {
+			bodyToken := parser.curToken
+			expression := parser.parseExpression(LOWEST)
+			if expression == nil {
+				return nil
+			}
+			statement := &ast.ExpressionStatement{Token: bodyToken, Expression: expression}
+			arm.Body = &ast.BlockStatement{Token: bodyToken, Statements: []ast.Statement{statement}}
+			parser.setSpan(statement, bodyToken)
+			parser.setSpan(arm.Body, bodyToken)
+		}
+		matchExpression.Arms = append(matchExpression.Arms, arm)
+		if !parser.peekTokenIs(token.R_BRACE) && !parser.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	if !parser.expectPeek(token.R_BRACE) {
+		return nil
+	}
+	return matchExpression
+}
+
 // IDENTIFIER
 // Identifiers are variable names
 // Example: age, first_name
 func (parser *Parser) parseIdentifier() ast.Expression {
+	identifier := parser.newIdentifier()
+	return identifier
+}
+
+// Creates an identifier from the current token, spanning that token
+func (parser *Parser) newIdentifier() *ast.Identifier {
 	identifier := &ast.Identifier{Token: parser.curToken, Value: parser.curToken.Literal}
+	parser.setSpan(identifier, parser.curToken)
 	return identifier
 }
 
 // INTEGER
-// Example: 10
+// Optional suffix i makes the type explicit
+// Example: 10, 10i
 func (parser *Parser) parseIntegerLiteral() ast.Expression {
 	integerLiteral := &ast.IntegerLiteral{Token: parser.curToken}
-	value, err := strconv.Atoi(parser.curToken.Literal)
+	value, err := strconv.Atoi(strings.TrimSuffix(parser.curToken.Literal, "i"))
 	if err != nil {
 		message := fmt.Sprintf("Could not parse %q as integer at %s", parser.curToken.Literal, parser.curToken.Location)
-		parser.errors = append(parser.errors, message)
+		parser.addError(parser.curToken.Location, message)
 		return nil
 	} else {
 		integerLiteral.Value = value
@@ -510,13 +937,14 @@ func (parser *Parser) parseIntegerLiteral() ast.Expression {
 }
 
 // FLOAT (64-bit)
-// Example: 10.28
+// Integer with suffix f is also a float
+// Example: 10.28, 10f
 func (parser *Parser) parseFloatLiteral() ast.Expression {
 	floatLiteral := &ast.FloatLiteral{Token: parser.curToken}
-	value, err := strconv.ParseFloat(parser.curToken.Literal, 64)
+	value, err := strconv.ParseFloat(strings.TrimSuffix(parser.curToken.Literal, "f"), 64)
 	if err != nil {
 		message := fmt.Sprintf("Could not parse %q as float at %s", parser.curToken.Literal, parser.curToken.Location)
-		parser.errors = append(parser.errors, message)
+		parser.addError(parser.curToken.Location, message)
 		return nil
 	} else {
 		floatLiteral.Value = value
@@ -539,6 +967,7 @@ func (parser *Parser) parseBooleanLiteral() ast.Expression {
 }
 
 // FN( PARAMETER, PARAMETER, ... ) { BODY }
+// Function being parsed is tracked, so that a yield in its body can mark it as a generator function
 // Example: fn(a, b) { a + b }
 func (parser *Parser) parseFunctionLiteral() ast.Expression {
 	functionLiteral := &ast.FunctionLiteral{Token: parser.curToken}
@@ -546,34 +975,108 @@ func (parser *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 	functionLiteral.Parameters = parser.parseFunctionParameters()
-	if !parser.expectPeek(token.L_BRACE) {
+	if functionLiteral.Parameters == nil || !parser.expectPeek(token.L_BRACE) {
 		return nil
 	}
+	parser.functions = append(parser.functions, functionLiteral)
 	functionLiteral.Body = parser.parseBlockStatement()
+	parser.functions = parser.functions[:len(parser.functions)-1]
+	if functionLiteral.Body == nil {
+		return nil
+	}
 	return functionLiteral
 }
 
-// ARRAY => [ ELEMENT, ELEMENT, ... ]
-// Example: [1, "FroLang", true]
+// ARRAY => [ ELEMENT, ELEMENT, ... ] / [ ELEMENT FOR IDENTIFIER IN ITERABLE <IF GUARD> ]
+// If the first element is followed by for, then it is an array comprehension
+// Example: [1, "FroLang", true], [num * num for num in range(0, 5) if num > 1]
 func (parser *Parser) parseArrayLiteral() ast.Expression {
 	arrayLiteral := &ast.ArrayLiteral{Token: parser.curToken}
-	arrayLiteral.Elements = parser.parseExpressionList(token.R_BRACKET)
+	if parser.peekTokenIs(token.R_BRACKET) {
+		parser.scanToken()
+		arrayLiteral.Elements = []ast.Expression{}
+		return arrayLiteral
+	}
+	parser.scanToken()
+	element := parser.parseListElement()
+	if element == nil {
+		return nil
+	}
+	if parser.peekTokenIs(token.FOR) {
+		comprehension := &ast.ComprehensionExpression{Token: arrayLiteral.Token, Value: element}
+		return parser.parseComprehension(comprehension, token.R_BRACKET)
+	}
+	arrayLiteral.Elements = parser.parseExpressionListTail([]ast.Expression{element}, token.R_BRACKET)
+	if arrayLiteral.Elements == nil {
+		return nil
+	}
 	return arrayLiteral
 }
 
-// HASH => { KEY: VALUE }
-// Example: {"language": "FroLang", "version": 1}
+// FOR IDENTIFIER IN ITERABLE <IF GUARD> END
+// Parses the rest of a comprehension, after its value (and key)
+// Example: for num in range(0, 5) if num > 1 ]
+func (parser *Parser) parseComprehension(comprehension *ast.ComprehensionExpression, endToken token.TokenType) ast.Expression {
+	parser.scanToken()
+	if !parser.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	comprehension.Element = parser.newIdentifier()
+	if !parser.expectPeek(token.IN) {
+		return nil
+	}
+	parser.scanToken()
+	comprehension.Iterator = parser.parseExpression(LOWEST)
+	if comprehension.Iterator == nil {
+		return nil
+	}
+	if parser.peekTokenIs(token.IF) {
+		parser.scanToken()
+		parser.scanToken()
+		comprehension.Guard = parser.parseExpression(LOWEST)
+		if comprehension.Guard == nil {
+			return nil
+		}
+	}
+	if !parser.expectPeek(endToken) {
+		return nil
+	}
+	return comprehension
+}
+
+// HASH => { KEY: VALUE } / { IDENTIFIER } / { KEY: VALUE FOR IDENTIFIER IN ITERABLE <IF GUARD> }
+// Key can be any expression which evaluates to a hashable value
+// An identifier without value is a shorthand for "IDENTIFIER": IDENTIFIER
+// If the first pair is followed by for, then it is a hash comprehension
+// Example: {"language": "FroLang", "version": 1}, {language, version}, {name: len(name) for name in names}
 func (parser *Parser) parseHashLiteral() ast.Expression {
 	hashLiteral := &ast.HashLiteral{Token: parser.curToken}
 	hashLiteral.Pairs = make(map[ast.Expression]ast.Expression)
 	for !parser.peekTokenIs(token.R_BRACE) {
 		parser.scanToken()
 		key := parser.parseExpression(LOWEST)
-		if !parser.expectPeek(token.COLON) {
+		if key == nil {
 			return nil
 		}
-		parser.scanToken()
-		value := parser.parseExpression(LOWEST)
+		var value ast.Expression
+		if identifier, ok := key.(*ast.Identifier); ok && (parser.peekTokenIs(token.COMMA) || parser.peekTokenIs(token.R_BRACE)) {
+			key = &ast.StringLiteral{Token: identifier.Token, Value: identifier.Value}
+			parser.setSpan(key, identifier.Token)
+			value = identifier
+		} else {
+			if !parser.expectPeek(token.COLON) {
+				return nil
+			}
+			parser.scanToken()
+			value = parser.parseExpression(LOWEST)
+			if value == nil {
+				return nil
+			}
+			if len(hashLiteral.Pairs) == 0 && parser.peekTokenIs(token.FOR) {
+				comprehension := &ast.ComprehensionExpression{Token: hashLiteral.Token, Key: key, Value: value}
+				return parser.parseComprehension(comprehension, token.R_BRACE)
+			}
+		}
 		hashLiteral.Pairs[key] = value
 		if !parser.peekTokenIs(token.R_BRACE) && !parser.expectPeek(token.COMMA) {
 			return nil
@@ -585,35 +1088,117 @@ func (parser *Parser) parseHashLiteral() ast.Expression {
 	return hashLiteral
 }
 
-// ITERABLE[INDEX]
-// Example: versions[0]
+// ITERABLE[INDEX] / ITERABLE?[INDEX]
+// If a colon follows the index (or the opening bracket), then it is a slice expression
+// With ?[ the expression is null, instead of an error, if the iterable is null
+// Example: versions[0], versions?[0]
 func (parser *Parser) parseIndexExpression(array ast.Expression) ast.Expression {
-	indexExpression := &ast.IndexExpression{Token: parser.curToken, Array: array}
+	indexExpression := &ast.IndexExpression{Token: parser.curToken, Array: array, Optional: parser.curTokenIs(token.Q_BRACKET)}
 	parser.scanToken()
+	if parser.curTokenIs(token.COLON) {
+		return parser.parseSliceExpression(indexExpression.Token, array, nil)
+	}
 	indexExpression.Index = parser.parseExpression(LOWEST)
+	if indexExpression.Index == nil {
+		return nil
+	}
+	if parser.peekTokenIs(token.COLON) {
+		parser.scanToken()
+		return parser.parseSliceExpression(indexExpression.Token, array, indexExpression.Index)
+	}
 	if !parser.expectPeek(token.R_BRACKET) {
 		return nil
 	}
 	return indexExpression
 }
 
-// VARIABLE = VALUE
-// Example: name = "FroLang"
-func (parser *Parser) parseAssignExpression(identifier ast.Expression) ast.Expression {
-	variable, ok := identifier.(*ast.Identifier)
-	if !ok {
-		message := fmt.Sprintf("Cannot assign value to a non-identifier")
-		parser.errors = append(parser.errors, message)
+// ITERABLE[<START>:<END><:STEP>]
+// Start, end and step are optional
+// Parsing begins at the colon, as the start (if any) is already parsed by parseIndexExpression
+// Example: versions[1:3], versions[:2], versions[::2]
+func (parser *Parser) parseSliceExpression(bracket token.Token, left ast.Expression, start ast.Expression) ast.Expression {
+	sliceExpression := &ast.SliceExpression{Token: bracket, Left: left, Start: start, Optional: bracket.Type == token.Q_BRACKET}
+	if !parser.peekTokenIs(token.R_BRACKET) && !parser.peekTokenIs(token.COLON) {
+		parser.scanToken()
+		sliceExpression.End = parser.parseExpression(LOWEST)
+		if sliceExpression.End == nil {
+			return nil
+		}
+	}
+	if parser.peekTokenIs(token.COLON) {
+		parser.scanToken()
+		if !parser.peekTokenIs(token.R_BRACKET) {
+			parser.scanToken()
+			sliceExpression.Step = parser.parseExpression(LOWEST)
+			if sliceExpression.Step == nil {
+				return nil
+			}
+		}
+	}
+	if !parser.expectPeek(token.R_BRACKET) {
+		return nil
+	}
+	return sliceExpression
+}
+
+// OBJECT.PROPERTY / OBJECT?.PROPERTY
+// With ?. the expression is null, instead of an error, if the object is null
+// Example: utils.add, user?.name
+func (parser *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	memberExpression := &ast.MemberExpression{Token: parser.curToken, Object: object, Optional: parser.curTokenIs(token.Q_DOT)}
+	if !parser.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	memberExpression.Property = parser.newIdentifier()
+	return memberExpression
+}
+
+// VARIABLE = VALUE / VARIABLE[<START>:<END>] = VALUE
+// Assigning to a slice of the variable replaces that range of the array held by it
+// Example: name = "FroLang", versions[1:3] = [2, 3]
+func (parser *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	assignExpression := ast.AssignExpression{Token: parser.curToken}
+	switch target := target.(type) {
+	case *ast.Identifier:
+		assignExpression.Variable = target
+	case *ast.SliceExpression:
+		variable, ok := target.Left.(*ast.Identifier)
+		if !ok {
+			message := fmt.Sprintf("Cannot assign value to a slice of a non-identifier at %s", parser.curToken.Location)
+			parser.addError(parser.curToken.Location, message)
+			return nil
+		}
+		assignExpression.Variable = variable
+		assignExpression.Slice = target
+	default:
+		message := fmt.Sprintf("Cannot assign value to a non-identifier at %s", parser.curToken.Location)
+		parser.addError(parser.curToken.Location, message)
 		return nil
 	}
 	parser.scanToken()
-	value := parser.parseExpression(LOWEST)
-	assignExpression := ast.AssignExpression{Token: parser.curToken, Variable: variable, Value: value}
+	// Parse the value with a precedence lower than ASSIGN, so that a = b = c is parsed as a = (b = c)
+	assignExpression.Value = parser.parseExpression(ASSIGN - 1)
+	if assignExpression.Value == nil {
+		return nil
+	}
 	return &assignExpression
 }
 
+// VARIABLE++ / VARIABLE--
+// Example: count++
+func (parser *Parser) parsePostfixExpression(target ast.Expression) ast.Expression {
+	variable, ok := target.(*ast.Identifier)
+	if !ok {
+		message := fmt.Sprintf("Cannot apply %s to a non-identifier at %s", parser.curToken.Literal, parser.curToken.Location)
+		parser.addError(parser.curToken.Location, message)
+		return nil
+	}
+	return &ast.PostfixExpression{Token: parser.curToken, Variable: variable, Operator: parser.curToken.Literal}
+}
+
 // ( EXPRESSION, EXPRESSION )
-// Example: (1, true)
+// Elements of the list can be spread expressions
+// Example: (1, true), (1, ...rest)
 func (parser *Parser) parseExpressionList(endToken token.TokenType) []ast.Expression {
 	arguments := []ast.Expression{}
 	if parser.peekTokenIs(endToken) {
@@ -621,11 +1206,24 @@ func (parser *Parser) parseExpressionList(endToken token.TokenType) []ast.Expres
 		return arguments
 	}
 	parser.scanToken()
-	arguments = append(arguments, parser.parseExpression(LOWEST))
+	argument := parser.parseListElement()
+	if argument == nil {
+		return nil
+	}
+	return parser.parseExpressionListTail(append(arguments, argument), endToken)
+}
+
+// , EXPRESSION, EXPRESSION )
+// Parses the rest of an expression list, after the already parsed expressions
+func (parser *Parser) parseExpressionListTail(arguments []ast.Expression, endToken token.TokenType) []ast.Expression {
 	for parser.peekTokenIs(token.COMMA) {
 		parser.scanToken()
 		parser.scanToken()
-		arguments = append(arguments, parser.parseExpression(LOWEST))
+		argument := parser.parseListElement()
+		if argument == nil {
+			return nil
+		}
+		arguments = append(arguments, argument)
 	}
 	if !parser.expectPeek(endToken) {
 		return nil
@@ -633,6 +1231,22 @@ func (parser *Parser) parseExpressionList(endToken token.TokenType) []ast.Expres
 	return arguments
 }
 
+// EXPRESSION / ...EXPRESSION
+// Parses an element of an array literal or an argument of a function call
+// Example: 1, ...numbers
+func (parser *Parser) parseListElement() ast.Expression {
+	if !parser.curTokenIs(token.ELLIPSIS) {
+		return parser.parseExpression(LOWEST)
+	}
+	spreadExpression := &ast.SpreadExpression{Token: parser.curToken}
+	parser.scanToken()
+	spreadExpression.Value = parser.parseExpression(LOWEST)
+	if spreadExpression.Value == nil {
+		return nil
+	}
+	return spreadExpression
+}
+
 // ( IDENTIFIER, IDENTIFIER )
 // Example: (language, version)
 func (parser *Parser) parseFunctionParameters() []*ast.Identifier {
@@ -642,12 +1256,12 @@ func (parser *Parser) parseFunctionParameters() []*ast.Identifier {
 		return identifiers
 	}
 	parser.scanToken()
-	identifier := &ast.Identifier{Token: parser.curToken, Value: parser.curToken.Literal}
+	identifier := parser.newIdentifier()
 	identifiers = append(identifiers, identifier)
 	for parser.peekTokenIs(token.COMMA) {
 		parser.scanToken()
 		parser.scanToken()
-		identifier := &ast.Identifier{Token: parser.curToken, Value: parser.curToken.Literal}
+		identifier := parser.newIdentifier()
 		identifiers = append(identifiers, identifier)
 	}
 	if !parser.expectPeek(token.R_PAREN) {
@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mochatek/frolang/ast"
+	"github.com/mochatek/frolang/lexer"
+)
+
+// Renders the expression with parentheses around every infix and assign expression, to show how it was grouped
+func grouped(expression ast.Expression) string {
+	switch expression := expression.(type) {
+	case *ast.InfixExpression:
+		return "(" + grouped(expression.Left) + " " + expression.Operator + " " + grouped(expression.Right) + ")"
+	case *ast.AssignExpression:
+		return "(" + expression.Variable.String() + " = " + grouped(expression.Value) + ")"
+	case *ast.PrefixExpression:
+		return "(" + expression.Operator + grouped(expression.Right) + ")"
+	default:
+		return expression.String()
+	}
+}
+
+// Parses the input, which must be a single expression statement, and returns its expression
+func parseExpression(t *testing.T, input string) ast.Expression {
+	t.Helper()
+	par := New(lexer.New(input))
+	program := par.ParseProgram()
+	if len(par.Errors()) != 0 {
+		t.Fatalf("Parse errors for %q: %v", input, par.Errors())
+	}
+	if len(program.Statements) != 1 {
+		t.Fatalf("Expected a single statement for %q, got %d", input, len(program.Statements))
+	}
+	statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("Expected an expression statement for %q, got %T", input, program.Statements[0])
+	}
+	return statement.Expression
+}
+
+// Compares the grouping of each input with the expected one
+func runPrecedenceTests(t *testing.T, tests []struct{ input, expected string }) {
+	t.Helper()
+	for _, test := range tests {
+		if result := grouped(parseExpression(t, test.input)); result != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.input, test.expected, result)
+		}
+	}
+}
+
+func TestAssignPrecedence(t *testing.T) {
+	runPrecedenceTests(t, []struct{ input, expected string }{
+		{"x = 1 == 1", "(x = (1 == 1))"},
+		{"a = b = 2", "(a = (b = 2))"},
+		{"x = y = 1 + 2", "(x = (y = (1 + 2)))"},
+		{"1 + 2 * 3", "(1 + (2 * 3))"},
+		{"-a * b", "((-a) * b)"},
+	})
+}
+
+func TestErrorsWithSource(t *testing.T) {
+	source := "let x = 1\n\tlet y = ;"
+	par := New(lexer.New(source))
+	par.ParseProgram()
+	expected := "No prefix parse function registered for ; at 2:10\n    \tlet y = ;\n    \t        ^"
+	if messages := par.ErrorsWithSource(source); len(messages) != 1 || messages[0] != expected {
+		t.Errorf("Expected %q, got %q", expected, messages)
+	}
+}
+
+// Malformed inputs are reported as errors instead of panicking
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"1 +", []string{"No prefix parse function registered for EOF at 1:4"}},
+		{"[1,,2]", []string{"No prefix parse function registered for , at 1:4", "No prefix parse function registered for ] at 1:6"}},
+		{"let x = 1\nlet y = ;", []string{"No prefix parse function registered for ; at 2:9"}},
+		{"f(1, ", []string{"No prefix parse function registered for EOF at 1:6"}},
+	}
+	for _, test := range tests {
+		par := New(lexer.New(test.input))
+		par.ParseProgram()
+		if !reflect.DeepEqual(par.Errors(), test.expected) {
+			t.Errorf("%q: expected %q, got %q", test.input, test.expected, par.Errors())
+		}
+	}
+}
+
+func TestHashLiteralKeys(t *testing.T) {
+	hash, ok := parseExpression(t, `{x, [1 + 1]: 2, "a": 3}`).(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("Expected a hash literal")
+	}
+	keys := []string{}
+	for key, value := range hash.Pairs {
+		keys = append(keys, key.String()+"="+value.String())
+	}
+	for _, expected := range []string{"x=x", "[1 + 1]=2", "a=3"} {
+		if !strings.Contains(strings.Join(keys, ","), expected) {
+			t.Errorf("Expected pair %s in %v", expected, keys)
+		}
+	}
+}
+
+// Index targets cannot be incremented, and a--b is a subtraction of a negated operand
+func TestPostfixParsing(t *testing.T) {
+	runPrecedenceTests(t, []struct{ input, expected string }{
+		{"a--b", "(a - (-b))"},
+		{"a-- - 1", "(a-- - 1)"},
+	})
+	par := New(lexer.New("items[0]++"))
+	par.ParseProgram()
+	if expected := []string{"Cannot apply ++ to a non-identifier at 1:9"}; !reflect.DeepEqual(par.Errors(), expected) {
+		t.Errorf("Expected %q, got %q", expected, par.Errors())
+	}
+}
+
+func TestGroupingAndTuples(t *testing.T) {
+	if _, ok := parseExpression(t, "(x)").(*ast.Identifier); !ok {
+		t.Errorf("Expected (x) to be a grouped identifier")
+	}
+	tests := []struct {
+		input    string
+		elements int
+	}{
+		{"(x,)", 1},
+		{"(1, 2)", 2},
+		{"(1, (2, 3), 4)", 3},
+	}
+	for _, test := range tests {
+		tuple, ok := parseExpression(t, test.input).(*ast.TupleLiteral)
+		if !ok {
+			t.Errorf("Expected %q to be a tuple", test.input)
+		} else if len(tuple.Elements) != test.elements {
+			t.Errorf("%q: expected %d elements, got %d", test.input, test.elements, len(tuple.Elements))
+		}
+	}
+}
+
+func TestSpans(t *testing.T) {
+	expression := parseExpression(t, "price  * count")
+	span := expression.Span()
+	if span.Start != (ast.Position{Line: 1, Column: 1}) || span.End != (ast.Position{Line: 1, Column: 14}) {
+		t.Errorf("Expected span to cover both operands, got %v", span)
+	}
+	program := New(lexer.New("let a = 1\nlet b = [\n  a\n]")).ParseProgram()
+	span = program.Statements[1].Span()
+	if span.Start != (ast.Position{Line: 2, Column: 1}) || span.End != (ast.Position{Line: 4, Column: 1}) {
+		t.Errorf("Expected span of let statement to cover its lines, got %v", span)
+	}
+}
+
+func TestCoalescePrecedence(t *testing.T) {
+	runPrecedenceTests(t, []struct{ input, expected string }{
+		{"x = a ?? b", "(x = (a ?? b))"},
+		{"a ?? b == c", "(a ?? (b == c))"},
+	})
+}
+
+func TestParseExpression(t *testing.T) {
+	expression, errors := New(lexer.New("1 + 2 * 3;")).ParseExpression()
+	if len(errors) != 0 || grouped(expression) != "(1 + (2 * 3))" {
+		t.Errorf("Expected (1 + (2 * 3)), got %v with errors %v", expression, errors)
+	}
+	for _, input := range []string{"1 + ", "1 + 2 3", "1; 2"} {
+		expression, errors := New(lexer.New(input)).ParseExpression()
+		if expression != nil || len(errors) == 0 {
+			t.Errorf("%q: expected an error, got %v", input, expression)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package repl
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mochatek/frolang/evaluator"
+	"github.com/mochatek/frolang/object"
+)
+
+// Prefix which marks a REPL input as meta-command instead of code
+const COMMAND_PREFIX = ":"
+
+// Meta-command of the REPL
+// Run is called with the text after the command name, and returns whether the REPL should stop
+type command struct {
+	usage       string
+	description string
+	run         func(session *session, argument string) bool
+}
+
+var commands = map[string]*command{}
+
+// Commands are registered here, as :help refers the commands map
+func init() {
+	commands["help"] = &command{usage: ":help", description: "Show the available commands and builtin functions", run: help}
+	commands["quit"] = &command{usage: ":quit", description: "Exit the REPL", run: quit}
+	commands["history"] = &command{usage: ":history [n]", description: "Show the previous inputs, or run the n-th input again", run: showHistory}
+	commands["load"] = &command{usage: ":load <path>", description: "Evaluate a .fro script in the current session", run: load}
+	commands["time"] = &command{usage: ":time <code>", description: "Evaluate the code and show how long it took", run: timeCode}
+	commands["precision"] = &command{usage: ":precision [n]", description: "Show or set the number of decimal places shown for floats", run: precision}
+	commands["trace"] = &command{usage: ":trace on|off", description: "Log each evaluated node to stderr", run: trace}
+	commands["profile"] = &command{usage: ":profile [on|off]", description: "Start or stop counting the calls of functions, or show the counts", run: profile}
+	commands["maxlen"] = &command{usage: ":maxlen [n]", description: "Show or set the maximum length of printed arrays/hashes. 0 means no limit", run: maxLength}
+}
+
+// Split the input into command name and its argument
+// Command names are case-insensitive
+// Run the command and return whether the REPL should stop
+// Unknown commands are reported as error
+func runCommand(session *session, input string) bool {
+	input = strings.TrimPrefix(strings.TrimSpace(input), COMMAND_PREFIX)
+	name, argument, _ := strings.Cut(input, " ")
+	cmd, ok := commands[strings.ToLower(name)]
+	if !ok {
+		writeError(session.out, "COMMAND ERROR: Unknown command %s%s. Use :help to see the available commands", COMMAND_PREFIX, name)
+		return false
+	}
+	return cmd.run(session, strings.TrimSpace(argument))
+}
+
+// Print the usage of each command, followed by the names of builtin functions
+func help(session *session, argument string) bool {
+	names := []string{}
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	io.WriteString(session.out, "Commands:\n")
+	for _, name := range names {
+		io.WriteString(session.out, fmt.Sprintf("  %-16s %s\n", commands[name].usage, commands[name].description))
+	}
+	io.WriteString(session.out, "Builtin functions:\n")
+	io.WriteString(session.out, "  "+strings.Join(evaluator.BuiltinNames(), ", ")+"\n")
+	return false
+}
+
+// Stop the REPL
+func quit(session *session, argument string) bool {
+	return true
+}
+
+// Without argument, print the previous inputs with their number
+// Otherwise, run the input with that number again and record it as the latest input
+func showHistory(session *session, argument string) bool {
+	entries := session.history.entries
+	if argument == "" {
+		for index, entry := range entries {
+			io.WriteString(session.out, fmt.Sprintf("%4d  %s\n", index+1, entry))
+		}
+		return false
+	}
+	number, err := strconv.Atoi(argument)
+	if err != nil || number < 1 || number > len(entries) {
+		writeError(session.out, "COMMAND ERROR: History has no input numbered %s", argument)
+		return false
+	}
+	input := entries[number-1]
+	io.WriteString(session.out, input+"\n")
+	return session.run(input)
+}
+
+// Evaluate the code in the session, and print the wall-clock duration after its result
+func timeCode(session *session, argument string) bool {
+	if argument == "" {
+		writeError(session.out, "COMMAND ERROR: Usage is %s", commands["time"].usage)
+		return false
+	}
+	start := time.Now()
+	session.evaluate(argument, true)
+	io.WriteString(session.out, fmt.Sprintf("Time: %s\n", time.Since(start)))
+	return false
+}
+
+// Read the script and evaluate it in the session environment, so that its variables stay available
+// While evaluating, imports in the script are resolved relative to the script
+func load(session *session, argument string) bool {
+	if argument == "" {
+		writeError(session.out, "COMMAND ERROR: Usage is %s", commands["load"].usage)
+		return false
+	}
+	sourceCode, err := ReadScript(argument)
+	if err != nil {
+		writeError(session.out, "SCRIPT ERROR: %s", err)
+		return false
+	}
+	previousPath := session.env.Path()
+	session.env.SetPath(argument)
+	defer session.env.SetPath(previousPath)
+	session.evaluate(sourceCode, true)
+	return false
+}
+
+// Without argument, print the current float precision. Otherwise, set it for the rest of the session
+func precision(session *session, argument string) bool {
+	if argument == "" {
+		io.WriteString(session.out, fmt.Sprintf("Precision: %d\n", object.FloatPrecision))
+		return false
+	}
+	digits, err := strconv.Atoi(argument)
+	if err != nil || digits < 0 {
+		writeError(session.out, "COMMAND ERROR: Precision must be a non-negative integer. Got %s", argument)
+		return false
+	}
+	object.FloatPrecision = digits
+	return false
+}
+
+// Without argument, print the current maximum output length. Otherwise, set it for the rest of the session
+func maxLength(session *session, argument string) bool {
+	if argument == "" {
+		io.WriteString(session.out, fmt.Sprintf("Max length: %d\n", object.MaxInspectLength))
+		return false
+	}
+	length, err := strconv.Atoi(argument)
+	if err != nil || length < 0 {
+		writeError(session.out, "COMMAND ERROR: Max length must be a non-negative integer. Got %s", argument)
+		return false
+	}
+	object.MaxInspectLength = length
+	return false
+}
+
+// Turn on or off logging of the evaluated nodes to stderr
+func trace(session *session, argument string) bool {
+	switch strings.ToLower(argument) {
+	case "on":
+		options := session.options()
+		options.Trace = os.Stderr
+		session.setOptions(options)
+	case "off":
+		options := session.options()
+		options.Trace = nil
+		session.setOptions(options)
+	default:
+		writeError(session.out, "COMMAND ERROR: Usage is %s", commands["trace"].usage)
+	}
+	return false
+}
+
+// Turn on or off the profiler. Without argument, print the calls and time of each function profiled so far
+func profile(session *session, argument string) bool {
+	switch strings.ToLower(argument) {
+	case "on":
+		options := session.options()
+		options.Debugger = evaluator.NewProfiler()
+		session.setOptions(options)
+	case "off":
+		options := session.options()
+		options.Debugger = nil
+		session.setOptions(options)
+	case "":
+		profiler, ok := session.options().Debugger.(*evaluator.Profiler)
+		if !ok {
+			writeError(session.out, "COMMAND ERROR: Profiler is off. Use :profile on to start it")
+			return false
+		}
+		io.WriteString(session.out, fmt.Sprintf("%-40s %8s %s\n", "Function", "Calls", "Time"))
+		for _, profile := range profiler.Report() {
+			io.WriteString(session.out, fmt.Sprintf("%-40s %8d %s\n", profile.Name, profile.Calls, profile.Time))
+		}
+	default:
+		writeError(session.out, "COMMAND ERROR: Usage is %s", commands["profile"].usage)
+	}
+	return false
+}
+
+// Write the message to output in red
+func writeError(out io.Writer, format string, arguments ...interface{}) {
+	io.WriteString(out, fmt.Sprintf("%s%s%s\n", RED, fmt.Sprintf(format, arguments...), RESET))
+}
@@ -0,0 +1,149 @@
+package repl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// Control keys understood by the line editor
+const (
+	ctrlC     = 3
+	ctrlD     = 4
+	ctrlH     = 8
+	escape    = 27
+	backspace = 127
+)
+
+// Line editor used when the REPL reads from a terminal
+// Up/down arrows move through the history, left/right arrows, home and end move the cursor
+// Backspace and delete remove a character. Ctrl + C, or Ctrl + D on an empty line, ends the input
+type lineEditor struct {
+	in      *bufio.Reader
+	out     io.Writer
+	history *history
+	// Switches the terminal to raw mode while a line is read, so that every key is received as it is pressed
+	raw func() (restore func(), err error)
+}
+
+func newLineEditor(in io.Reader, out io.Writer, hist *history) *lineEditor {
+	return &lineEditor{in: bufio.NewReader(in), out: out, history: hist}
+}
+
+// Show the prompt and edit the line until enter is pressed
+// Line being typed is kept as draft while a history entry is shown, and is restored after the newest entry
+// Returns false if the input ended
+func (editor *lineEditor) readLine(prompt string) (string, bool) {
+	if editor.raw != nil {
+		if restore, err := editor.raw(); err == nil {
+			defer restore()
+		}
+	}
+	io.WriteString(editor.out, prompt)
+
+	line, draft := []rune{}, []rune{}
+	cursor := 0
+	position := len(editor.history.entries)
+	show := func(entry []rune) {
+		line = append([]rune{}, entry...)
+		cursor = len(line)
+	}
+	for {
+		char, _, err := editor.in.ReadRune()
+		if err != nil {
+			io.WriteString(editor.out, "\n")
+			return string(line), len(line) > 0
+		}
+		switch char {
+		case '\r', '\n':
+			io.WriteString(editor.out, "\n")
+			return string(line), true
+		case ctrlC:
+			io.WriteString(editor.out, "\n")
+			return "", false
+		case ctrlD:
+			if len(line) == 0 {
+				io.WriteString(editor.out, "\n")
+				return "", false
+			}
+			if cursor < len(line) {
+				line = append(line[:cursor], line[cursor+1:]...)
+			}
+		case backspace, ctrlH:
+			if cursor > 0 {
+				line = append(line[:cursor-1], line[cursor:]...)
+				cursor--
+			}
+		case escape:
+			switch editor.readEscape() {
+			case "A":
+				if position > 0 {
+					if position == len(editor.history.entries) {
+						draft = line
+					}
+					position--
+					show([]rune(editor.history.entries[position]))
+				}
+			case "B":
+				if position < len(editor.history.entries) {
+					position++
+					if position == len(editor.history.entries) {
+						show(draft)
+					} else {
+						show([]rune(editor.history.entries[position]))
+					}
+				}
+			case "C":
+				if cursor < len(line) {
+					cursor++
+				}
+			case "D":
+				if cursor > 0 {
+					cursor--
+				}
+			case "H", "1~":
+				cursor = 0
+			case "F", "4~":
+				cursor = len(line)
+			case "3~":
+				if cursor < len(line) {
+					line = append(line[:cursor], line[cursor+1:]...)
+				}
+			}
+		default:
+			if unicode.IsPrint(char) {
+				line = append(line[:cursor], append([]rune{char}, line[cursor:]...)...)
+				cursor++
+			}
+		}
+		editor.refresh(prompt, line, cursor)
+	}
+}
+
+// Read the rest of an escape sequence, and return it without the leading ESC [ or ESC O
+// Sequence ends with a letter or ~. Eg: A for up arrow, 3~ for delete
+func (editor *lineEditor) readEscape() string {
+	if next, _, err := editor.in.ReadRune(); err != nil || (next != '[' && next != 'O') {
+		return ""
+	}
+	sequence := []rune{}
+	for {
+		char, _, err := editor.in.ReadRune()
+		if err != nil {
+			return ""
+		}
+		sequence = append(sequence, char)
+		if unicode.IsLetter(char) || char == '~' {
+			return string(sequence)
+		}
+	}
+}
+
+// Redraw the line after the prompt, clear the rest of the terminal line, and move the cursor back to its position
+func (editor *lineEditor) refresh(prompt string, line []rune, cursor int) {
+	io.WriteString(editor.out, "\r"+prompt+string(line)+"\033[K")
+	if back := len(line) - cursor; back > 0 {
+		io.WriteString(editor.out, fmt.Sprintf("\033[%dD", back))
+	}
+}
@@ -0,0 +1,42 @@
+package repl
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLineEditor(t *testing.T) {
+	hist := &history{entries: []string{"let x = 1", "x + 2"}}
+	tests := []struct {
+		keys     string
+		expected string
+	}{
+		{"abc\r", "abc"},
+		{"\x1b[A\r", "x + 2"},
+		{"\x1b[A\x1b[A\r", "let x = 1"},
+		{"\x1b[A\x1b[A\x1b[A\r", "let x = 1"},
+		{"\x1b[A\x1b[A\x1b[B\r", "x + 2"},
+		{"draft\x1b[A\x1b[B\r", "draft"},
+		{"ac\x1b[Db\r", "abc"},
+		{"abc\x7f\x7f\r", "a"},
+		{"abc\x1b[D\x1b[D\x1b[3~\r", "ac"},
+		{"bc\x1b[Ha\x1b[Fd\r", "abcd"},
+		{"héllo\x1b[D\x7f\r", "hélo"},
+	}
+	for _, test := range tests {
+		editor := newLineEditor(strings.NewReader(test.keys), io.Discard, hist)
+		if line, ok := editor.readLine(PROMPT); !ok || line != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.keys, test.expected, line)
+		}
+	}
+}
+
+func TestLineEditorEnd(t *testing.T) {
+	for _, keys := range []string{"", "\x03", "abc\x03", "\x04"} {
+		editor := newLineEditor(strings.NewReader(keys), io.Discard, &history{})
+		if _, ok := editor.readLine(PROMPT); ok {
+			t.Errorf("%q: expected end of input", keys)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package repl
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// File to which the REPL inputs are saved, so that they are available in the next session
+// Set it to empty string to disable saving the history
+var HistoryFile = defaultHistoryFile()
+
+// Maximum number of inputs loaded from the history file
+var MaxHistory = 500
+
+// Inputs entered in the REPL, oldest first
+type history struct {
+	entries []string
+}
+
+// History file is kept in the home directory of the user
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".frolang_history")
+}
+
+// Read the entries of the previous sessions from the history file
+// Only the last MaxHistory entries are kept, and the file is truncated to them, so that it does not grow forever
+// Missing file means that there is no history yet
+func loadHistory() *history {
+	hist := &history{}
+	if HistoryFile == "" {
+		return hist
+	}
+	file, err := os.Open(HistoryFile)
+	if err != nil {
+		return hist
+	}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.TrimSpace(line) != "" {
+			hist.entries = append(hist.entries, line)
+		}
+	}
+	file.Close()
+	if len(hist.entries) > MaxHistory {
+		hist.entries = hist.entries[len(hist.entries)-MaxHistory:]
+		os.WriteFile(HistoryFile, []byte(strings.Join(hist.entries, "\n")+"\n"), 0600)
+	}
+	return hist
+}
+
+// Record the input and append it to the history file
+// Empty inputs are ignored. History still works in memory if the file cannot be written
+func (hist *history) add(input string) {
+	if strings.TrimSpace(input) == "" {
+		return
+	}
+	hist.entries = append(hist.entries, input)
+	if HistoryFile == "" {
+		return
+	}
+	file, err := os.OpenFile(HistoryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	file.WriteString(input + "\n")
+}
@@ -4,9 +4,11 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"os"
 	"runtime"
 	"strings"
 
+	"github.com/mochatek/frolang/analyzer"
 	"github.com/mochatek/frolang/evaluator"
 	"github.com/mochatek/frolang/lexer"
 	"github.com/mochatek/frolang/object"
@@ -19,56 +21,126 @@ const PROMPT = ">> "
 var RESET = "\033[0m"
 var RED = "\033[31m"
 var GREEN = "\033[32m"
+var YELLOW = "\033[33m"
 
-// Creates the global environment
+// State of a REPL session, which is shared with the commands
+type session struct {
+	env     *object.Environment
+	out     io.Writer
+	history *history
+}
+
+// Creates the session with global environment and the history of previous sessions
+// Input from a terminal is read by the line editor, so that up/down arrows bring back the previous inputs
 // Enters the loop
-// Take input statement form user
-// Lexer will tokenize the input
-// Parser will read tokens through lexer and constructs the program AST
-// If there were any parse errors, we will display it
-// Else, evaluator will evaluate the program AST and displays the result
+// Take input statement form user and run it
 // Ask user for next input
-// Ctrl + C input will terminate the loop
+// Ctrl + C input or :quit will terminate the loop
 func Start(in io.Reader, out io.Writer) {
 	// Windows doesn't natively support color in cmd
-	if runtime.GOOS == "windows" {
+	// Also respect the user's preference to disable color (https://no-color.org)
+	if runtime.GOOS == "windows" || os.Getenv("NO_COLOR") != "" {
 		RESET = ""
 		RED = ""
 		GREEN = ""
+		YELLOW = ""
+		evaluator.UseColor = false
 	}
 
 	fmt.Printf("%s%s%s\n", GREEN, HEADER, RESET)
 	fmt.Println(strings.Repeat("-", len(HEADER)-2))
 
-	scanner := bufio.NewScanner(in)
-	env := object.NewEnvironment()
+	session := &session{env: object.NewEnvironment(), out: out, history: loadHistory()}
+	readLine := scanLines(in)
+	if file, ok := in.(*os.File); ok && isTerminal(int(file.Fd())) {
+		editor := newLineEditor(in, out, session.history)
+		editor.raw = func() (func(), error) { return makeRaw(int(file.Fd())) }
+		readLine = editor.readLine
+	}
 
 	for {
-		fmt.Printf(PROMPT)
-		scanned := scanner.Scan()
-		if !scanned {
+		input, ok := readLine(PROMPT)
+		if !ok {
+			evaluator.CloseFiles()
+			return
+		}
+		if session.run(input) {
+			evaluator.CloseFiles()
 			return
 		}
+	}
+}
+
+// Returns a function which shows the prompt and reads the next line of the input
+// Used when the input is not a terminal, so lines cannot be edited
+func scanLines(in io.Reader) func(prompt string) (string, bool) {
+	scanner := bufio.NewScanner(in)
+	return func(prompt string) (string, bool) {
+		fmt.Print(prompt)
+		if !scanner.Scan() {
+			return "", false
+		}
+		return scanner.Text(), true
+	}
+}
+
+// Record the input in history, unless it is a :history command
+// If it is a meta-command like :help, then run it and return whether the REPL should stop
+// Otherwise, evaluate it as code. Result is not shown if the code ends with semicolon
+func (session *session) run(input string) bool {
+	trimmed := strings.TrimSpace(input)
+	if !strings.HasPrefix(strings.ToLower(trimmed), COMMAND_PREFIX+"history") {
+		session.history.add(input)
+	}
+	if strings.HasPrefix(trimmed, COMMAND_PREFIX) {
+		return runCommand(session, input)
+	}
+	session.evaluate(input, !strings.HasSuffix(trimmed, ";"))
+	return false
+}
+
+// Returns a copy of the evaluation options of the session, which can be changed and set back using setOptions
+func (session *session) options() evaluator.Options {
+	if options, ok := session.env.Options().(*evaluator.Options); ok {
+		return *options
+	}
+	return evaluator.Options{}
+}
 
-		code := scanner.Text()
-		lex := lexer.New(code)
-		par := parser.New(lex)
-		program := par.ParseProgram()
+// Sets the evaluation options of the session
+// Options are replaced instead of changed in place, as tasks spawned by the previous inputs may still be reading them
+func (session *session) setOptions(options evaluator.Options) {
+	session.env.SetOptions(&options)
+}
 
-		if len(par.Errors()) != 0 {
-			for _, message := range par.Errors() {
-				io.WriteString(out, fmt.Sprintf("%sPARSE ERROR: %s%s\n", RED, message, RESET))
-			}
-			continue
+// Lexer will tokenize the code
+// Parser will read tokens through lexer and constructs the program AST
+// If there were any parse errors, we will display it
+// Else, evaluator will evaluate the program AST, followed by the warnings of the analyzer
+// Warnings are about undefined variables and unused local variables, except what the error already reports
+// Errors are always displayed, whereas the result is displayed only if asked to show it and it is not null, like that of print
+func (session *session) evaluate(code string, showResult bool) {
+	lex := lexer.New(code)
+	par := parser.New(lex)
+	program := par.ParseProgram()
+
+	if len(par.Errors()) != 0 {
+		for _, message := range par.ErrorsWithSource(code) {
+			io.WriteString(session.out, fmt.Sprintf("%sPARSE ERROR: %s%s\n", RED, message, RESET))
 		}
+		return
+	}
 
-		result := evaluator.Eval(program, env)
-		if result != nil {
-			if result.Type() == object.ERROR_OBJ {
-				io.WriteString(out, fmt.Sprintf("%s%s%s\n", RED, result.Inspect(), RESET))
-			} else {
-				io.WriteString(out, fmt.Sprintf("%s%s%s\n", GREEN, result.Inspect(), RESET))
-			}
+	warnings := analyzer.AnalyzeIn(program, session.env)
+	result := evaluator.SafeEval(program, session.env)
+	for _, message := range analyzer.Unreported(warnings, result) {
+		io.WriteString(session.out, fmt.Sprintf("%sWARNING: %s%s\n", YELLOW, message, RESET))
+	}
+	if result != nil && result.Type() != object.NULL_OBJ {
+		if result.Type() == object.ERROR_OBJ {
+			io.WriteString(session.out, fmt.Sprintf("%s%s%s\n", RED, result.Inspect(), RESET))
+		} else if showResult {
+			io.WriteString(session.out, fmt.Sprintf("%s%s%s\n", GREEN, result.Inspect(), RESET))
 		}
 	}
 }
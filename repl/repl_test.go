@@ -0,0 +1,167 @@
+package repl
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mochatek/frolang/evaluator"
+	"github.com/mochatek/frolang/object"
+)
+
+// Colors are turned off and the history is kept in a temporary file, so that the tests don't touch the user's history
+func TestMain(m *testing.M) {
+	RESET, RED, GREEN, YELLOW = "", "", "", ""
+	evaluator.UseColor = false
+	directory, err := os.MkdirTemp("", "frolang")
+	if err != nil {
+		panic(err)
+	}
+	HistoryFile = filepath.Join(directory, "history")
+	code := m.Run()
+	os.RemoveAll(directory)
+	os.Exit(code)
+}
+
+// Runs Start on the input, with the header and prompts discarded, and returns its output
+func startInput(input string) string {
+	stdout := os.Stdout
+	os.Stdout, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	defer func() { os.Stdout = stdout }()
+
+	var out strings.Builder
+	Start(strings.NewReader(input), &out)
+	return out.String()
+}
+
+// Start reads the inputs from a piped reader until :quit or the end of input
+func TestStart(t *testing.T) {
+	if output := startInput("let x = 5\nx * 2\n:quit\nx * 3\n"); output != "10\n" {
+		t.Errorf("Expected evaluation to stop at :quit, got %q", output)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	if output := startInput(":HELP\n"); !strings.Contains(output, ":quit") || !strings.Contains(output, "Builtin functions:") {
+		t.Errorf("Expected :help to list the commands, got %q", output)
+	}
+	help := startInput(":help\n")
+	for _, name := range []string{"print", "map", "maxBy", "spawn", "next", "globals", "locals"} {
+		if !strings.Contains(help, " "+name+",") {
+			t.Errorf("Expected :help to list the builtin %s, got %q", name, help)
+		}
+	}
+	if output := startInput(":nope\n"); output != "COMMAND ERROR: Unknown command :nope. Use :help to see the available commands\n" {
+		t.Errorf("Expected unknown command error, got %q", output)
+	}
+}
+
+// Runs the inputs in a new session, and returns its output along with whether the REPL was asked to stop
+func runInputs(inputs ...string) (string, bool) {
+	var out strings.Builder
+	session := &session{env: object.NewEnvironment(), out: &out, history: &history{}}
+	for _, input := range inputs {
+		if session.run(input) {
+			return out.String(), true
+		}
+	}
+	return out.String(), false
+}
+
+// Inputs are saved to the history file and loaded by the next session
+func TestHistory(t *testing.T) {
+	os.Remove(HistoryFile)
+	startInput("let a = 1\n\na + 1\n:history\n")
+	if entries := loadHistory().entries; strings.Join(entries, "|") != "let a = 1|a + 1" {
+		t.Errorf("Expected the inputs to be reloaded, got %q", entries)
+	}
+
+	var out strings.Builder
+	session := &session{env: object.NewEnvironment(), out: &out, history: loadHistory()}
+	session.run(":history")
+	session.run(":history 2")
+	session.run(":history 9")
+	if expected := "   1  let a = 1\n   2  a + 1\na + 1\nEVAL ERROR: Identifier: a not found at 1:1\nCOMMAND ERROR: History has no input numbered 9\n"; out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+	if _, stopped := runInputs(":quit", "1"); !stopped {
+		t.Errorf("Expected :quit to stop the REPL")
+	}
+}
+
+// History file is truncated to the last MaxHistory entries when it is loaded
+func TestHistoryTruncation(t *testing.T) {
+	defer func(max int) { MaxHistory = max }(MaxHistory)
+	MaxHistory = 2
+	os.Remove(HistoryFile)
+	startInput("1\n2\n3\n4\n")
+	if entries := loadHistory().entries; strings.Join(entries, "|") != "3|4" {
+		t.Errorf("Expected the last 2 inputs to be loaded, got %q", entries)
+	}
+	if content, _ := os.ReadFile(HistoryFile); string(content) != "3\n4\n" {
+		t.Errorf("Expected the history file to be truncated, got %q", content)
+	}
+}
+
+func TestTimeCommand(t *testing.T) {
+	if output, _ := runInputs(":time 2 * 3"); !regexp.MustCompile(`^6\nTime: [0-9.]+[µnm]?s\n$`).MatchString(output) {
+		t.Errorf("Expected result and duration, got %q", output)
+	}
+}
+
+func TestLoadCommand(t *testing.T) {
+	directory := t.TempDir()
+	valid := filepath.Join(directory, "valid.fro")
+	invalid := filepath.Join(directory, "invalid.fro")
+	os.WriteFile(valid, []byte("let loaded = 42;"), 0o644)
+	os.WriteFile(invalid, []byte("let = 1"), 0o644)
+
+	if output, _ := runInputs(":load "+valid, "loaded"); output != "42\n" {
+		t.Errorf("Expected variable of the loaded script, got %q", output)
+	}
+	if output, _ := runInputs(":load " + invalid); !strings.HasPrefix(output, "PARSE ERROR: ") {
+		t.Errorf("Expected parse error, got %q", output)
+	}
+	if output, _ := runInputs(":load " + filepath.Join(directory, "missing.fro")); !strings.HasPrefix(output, "SCRIPT ERROR: ") {
+		t.Errorf("Expected script error, got %q", output)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		inputs   []string
+		expected string
+	}{
+		{[]string{"1 + 2"}, "3\n"},
+		{[]string{"1 + 2;"}, ""},
+		{[]string{"let x = 2;", "x * 3"}, "6\n"},
+		{[]string{"1 / 0;"}, "EVAL ERROR: Division by 0 is not allowed\n"},
+		{[]string{"1 +", "2"}, "PARSE ERROR: No prefix parse function registered for EOF at 1:4\n    1 +\n       ^\n2\n"},
+		{[]string{"nope", "1"}, "EVAL ERROR: Identifier: nope not found at 1:1\n1\n"},
+		{[]string{`{}["none"]`, "fn() { }()"}, ""},
+	}
+	for _, test := range tests {
+		if output, _ := runInputs(test.inputs...); output != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.inputs, test.expected, output)
+		}
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	if output, _ := runInputs("fn() { let unused = 1; 2 }()"); output != "WARNING: Unused variable: unused at 1:12\n2\n" {
+		t.Errorf("Expected warning before the result, got %q", output)
+	}
+	if output, _ := runInputs("nope"); output != "EVAL ERROR: Identifier: nope not found at 1:1\n" {
+		t.Errorf("Expected the error alone, got %q", output)
+	}
+}
+
+// Profiler is an option of the session, which is turned on and off by :profile
+func TestProfileCommand(t *testing.T) {
+	output, _ := runInputs(":profile", ":profile on", "let f = fn() { 1 }; f(); f();", ":profile", ":profile off", ":profile")
+	if !regexp.MustCompile(`^COMMAND ERROR: Profiler is off.*\nFunction +Calls Time\nf \(1:14\) +2 .*\nCOMMAND ERROR: Profiler is off`).MatchString(output) {
+		t.Errorf("Expected the calls of f between :profile on and off, got %q", output)
+	}
+}
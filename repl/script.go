@@ -0,0 +1,23 @@
+package repl
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Extension of FroLang script files
+const SCRIPT_EXTENSION = "fro"
+
+// Read the source code of a FroLang script
+// Return error if the file doesn't have .fro extension or it cannot be read
+func ReadScript(path string) (string, error) {
+	if parts := strings.Split(path, "."); strings.ToLower(parts[len(parts)-1]) != SCRIPT_EXTENSION {
+		return "", fmt.Errorf("%s is not a valid FroLang script.\n\tFile extension should be: .%s", path, SCRIPT_EXTENSION)
+	}
+	contentBytes, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(contentBytes), nil
+}
@@ -0,0 +1,6 @@
+package repl
+
+import "syscall"
+
+const ioctlGetTermios = syscall.TCGETS
+const ioctlSetTermios = syscall.TCSETS
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin
+
+package repl
+
+import "errors"
+
+// Line editing is not supported on this platform, so the REPL reads plain lines
+func isTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int) (func(), error) {
+	return nil, errors.New("raw mode is not supported")
+}
@@ -2,10 +2,12 @@ package token
 
 type TokenType string
 
+// Location and End are the line:col of the first and last character of the token
 type Token struct {
 	Type     TokenType
 	Literal  string
 	Location string
+	End      string
 }
 
 // Identifiers and Literals
@@ -20,12 +22,14 @@ const (
 
 // Arithmetic Operators
 const (
-	PLUS     = "+"
-	MINUS    = "-"
-	ASTERISK = "*"
-	SLASH    = "/"
-	BANG     = "!"
-	ASSIGN   = "="
+	PLUS      = "+"
+	MINUS     = "-"
+	ASTERISK  = "*"
+	SLASH     = "/"
+	BANG      = "!"
+	ASSIGN    = "="
+	INCREMENT = "++"
+	DECREMENT = "--"
 )
 
 // Comparison Operators
@@ -40,8 +44,9 @@ const (
 
 // Logical Operators
 const (
-	AND = "&"
-	OR  = "|"
+	AND      = "&"
+	OR       = "|"
+	COALESCE = "??"
 )
 
 // Parentheses, Braces and Special characters
@@ -55,6 +60,11 @@ const (
 	COMMA     = ","
 	SEMICOLON = ";"
 	COLON     = ":"
+	DOT       = "."
+	Q_DOT     = "?."
+	Q_BRACKET = "?["
+	ELLIPSIS  = "..."
+	ARROW     = "=>"
 	O_COMMENT = "/*"
 	C_COMMENT = "*/"
 )
@@ -74,6 +84,13 @@ const (
 	TRY      = "TRY"
 	CATCH    = "CATCH"
 	FINALLY  = "FINALLY"
+	IMPORT   = "IMPORT"
+	AS       = "AS"
+	EXPORT   = "EXPORT"
+	DEFER    = "DEFER"
+	WITH     = "WITH"
+	MATCH    = "MATCH"
+	YIELD    = "YIELD"
 )
 
 // Others
@@ -98,6 +115,13 @@ var Keywords = map[string]TokenType{
 	"try":      TRY,
 	"catch":    CATCH,
 	"finally":  FINALLY,
+	"import":   IMPORT,
+	"as":       AS,
+	"export":   EXPORT,
+	"defer":    DEFER,
+	"with":     WITH,
+	"match":    MATCH,
+	"yield":    YIELD,
 }
 
 // Helper function to lookup a word in keyword dictionary